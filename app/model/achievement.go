@@ -6,20 +6,59 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	AchievementTypeCompetition   = "competition"
+	AchievementTypePublication   = "publication"
+	AchievementTypeOrganization  = "organization"
+	AchievementTypeCertification = "certification"
+	AchievementTypeOther         = "other"
+)
+
+const (
+	CertificationExpiryExclude = "exclude"
+	CertificationExpiryKeep    = "keep"
+)
+
 type Achievement struct {
 	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	StudentID        string             `bson:"studentId" json:"studentId"`
 	AchievementType  string             `bson:"achievementType" json:"achievementType"`
+	TypeVersion      int                `bson:"typeVersion,omitempty" json:"typeVersion,omitempty"`
 	Title            string             `bson:"title" json:"title"`
 	Description      string             `bson:"description" json:"description"`
 	Details          AchievementDetails `bson:"details,omitempty" json:"details,omitempty"`
 	Attachments      []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
 	Tags             []string           `bson:"tags,omitempty" json:"tags,omitempty"`
 	Points           int                `bson:"points,omitempty" json:"points,omitempty"`
+	ReferencePoints  map[string]int     `bson:"referencePoints,omitempty" json:"-"`
+	IsTeam           bool               `bson:"isTeam,omitempty" json:"isTeam,omitempty"`
+	TeamMembers      []string           `bson:"teamMembers,omitempty" json:"teamMembers,omitempty"`
+	TeamVerification string             `bson:"teamVerification,omitempty" json:"teamVerification,omitempty"`
+	RenewalOf        string             `bson:"renewalOf,omitempty" json:"renewalOf,omitempty"`
+	ExpiredAt        *time.Time         `bson:"expiredAt,omitempty" json:"expiredAt,omitempty"`
+	ExpiryWarnedAt   *time.Time         `bson:"expiryWarnedAt,omitempty" json:"-"`
+	PointsExcluded   bool               `bson:"pointsExcluded,omitempty" json:"pointsExcluded,omitempty"`
+	Version          int                `bson:"version,omitempty" json:"version"`
 	CreatedAt        time.Time          `bson:"createdAt,omitempty" json:"createdAt,omitempty"`
 	UpdatedAt        time.Time          `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
 }
 
+// PointsFor mengembalikan points hasil verifikasi untuk satu reference.
+// Dokumen lama hanya menyimpan points bersama di level dokumen.
+func (a Achievement) PointsFor(refID string) int {
+	if p, ok := a.ReferencePoints[refID]; ok {
+		return p
+	}
+	return a.Points
+}
+
+// ForReference mengembalikan salinan dokumen dengan Points milik reference
+// tersebut, untuk ditampilkan bersama data reference.
+func (a Achievement) ForReference(refID string) Achievement {
+	a.Points = a.PointsFor(refID)
+	return a
+}
+
 type AchievementDetails struct {
 	CompetitionName  string     `bson:"competitionName,omitempty" json:"competitionName,omitempty"`
 	CompetitionLevel string     `bson:"competitionLevel,omitempty" json:"competitionLevel,omitempty"`
@@ -61,13 +100,16 @@ type Attachment struct {
 }
 
 type CreateAchievementRequest struct {
-	Title           string                 `json:"title" validate:"required"`
-	Description     string                 `json:"description,omitempty"`
-	AchievementType string                 `json:"achievement_type" validate:"required"`
-	Details         map[string]interface{} `json:"details,omitempty"`
-	Tags            []string               `json:"tags,omitempty"`
-	Points          int                    `json:"points,omitempty"`
-	Attachments     []Attachment           `json:"attachments,omitempty"`
+	Title            string                 `json:"title" validate:"required"`
+	Description      string                 `json:"description,omitempty"`
+	AchievementType  string                 `json:"achievement_type" validate:"required"`
+	Details          map[string]interface{} `json:"details,omitempty"`
+	Tags             []string               `json:"tags,omitempty"`
+	Points           int                    `json:"points,omitempty"`
+	Attachments      []Attachment           `json:"attachments,omitempty"`
+	TeamMemberIDs    []string               `json:"team_member_ids,omitempty"`
+	TeamVerification string                 `json:"team_verification,omitempty"`
+	RenewalOf        string                 `json:"renewal_of,omitempty"`
 }
 
 type UpdateAchievementRequest struct {
@@ -86,9 +128,57 @@ type AchievementDetailResponse struct {
 	Achievement        Achievement  `json:"achievement"`
 	ReferenceStatus    string       `json:"status"`
 	SubmittedAt        *time.Time   `json:"submitted_at,omitempty"`
+	ReviewStartedAt    *time.Time   `json:"review_started_at,omitempty"`
 	VerifiedAt         *time.Time   `json:"verified_at,omitempty"`
 	VerifiedBy         *string      `json:"verified_by,omitempty"`
 	RejectionNote      *string      `json:"rejection_note,omitempty"`
+	DeletedAt          *time.Time   `json:"deleted_at,omitempty"`
+	SLARemindedAt      *time.Time   `json:"-"`
+	EscalatedAt        *time.Time   `json:"-"`
+	SLA                *SLAStatus   `json:"sla,omitempty"`
+	DocumentMissing    bool         `json:"document_missing,omitempty"`
+	Version            int          `json:"version"`
 	CreatedAtRef       time.Time    `json:"created_at_ref"`
 	UpdatedAtRef       time.Time    `json:"updated_at_ref"`
-}
\ No newline at end of file
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type StatusHistoryEntry struct {
+	ID         string    `json:"id"`
+	FromStatus *string   `json:"from_status,omitempty"`
+	ToStatus   string    `json:"status"`
+	ActorID    *string   `json:"actor,omitempty"`
+	ActorName  *string   `json:"actor_name,omitempty"`
+	Note       *string   `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"timestamp"`
+}
+
+type PurgeResult struct {
+	ReferenceID     string `json:"reference_id"`
+	Status          string `json:"status"`
+	Error           string `json:"error,omitempty"`
+	DocumentDeleted  bool   `json:"document_deleted"`
+	RevisionsDeleted int64  `json:"revisions_deleted"`
+	FilesRemoved     int    `json:"files_removed"`
+}
+
+type ReopenAchievementRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ReopenedReference struct {
+	ReferenceID string `json:"reference_id"`
+	StudentID   string `json:"student_id"`
+	AdvisorID   string `json:"advisor_id,omitempty"`
+	Version     int    `json:"version"`
+}
+
+// ReferenceVersion adalah id reference beserta versinya setelah status berubah.
+type ReferenceVersion struct {
+	ID      string
+	Version int
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// AchievementExportRow adalah satu baris ekspor: data reference dan nama
+// terkait dari PostgreSQL ditambah dokumen MongoDB.
+type AchievementExportRow struct {
+	ReferenceID   string
+	MongoID       string
+	Status        string
+	StudentNIM    string
+	StudentName   string
+	StudyProgram  string
+	YearOfEntry   int
+	AdvisorName   string
+	VerifierName  string
+	RejectionNote string
+	CreatedAt     time.Time
+	SubmittedAt   *time.Time
+	VerifiedAt    *time.Time
+	Achievement   *Achievement
+}
@@ -0,0 +1,39 @@
+package model
+
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// AchievementFacets berisi jumlah per nilai untuk setiap dimensi. Semua
+// dimensi dihitung per reference, sama seperti total daftar, sehingga
+// prestasi tim terhitung untuk tiap anggota.
+type AchievementFacets struct {
+	AchievementType  []FacetBucket `json:"achievement_type"`
+	CompetitionLevel []FacetBucket `json:"competition_level"`
+	MedalType        []FacetBucket `json:"medal_type"`
+	Status           []FacetBucket `json:"status"`
+	Year             []FacetBucket `json:"year"`
+	StudyProgram     []FacetBucket `json:"study_program"`
+}
+
+// ReferenceFacetKey adalah data reference yang dibutuhkan untuk facet.
+type ReferenceFacetKey struct {
+	MongoID      string
+	Status       string
+	StudyProgram string
+}
+
+// DocumentFacetFields adalah nilai dimensi facet yang berasal dari dokumen
+// MongoDB.
+type DocumentFacetFields struct {
+	AchievementType  string
+	CompetitionLevel string
+	MedalType        string
+	Year             string
+}
+
+type AchievementFacetResponse struct {
+	Items  []AchievementDetailResponse `json:"items"`
+	Facets AchievementFacets           `json:"facets"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// AchievementListFilter adalah filter daftar prestasi di PostgreSQL. Tanpa
+// Statuses, reference deleted tidak ikut. MongoIDs dipakai jika
+// RestrictToMongoIDs true (hasil prefilter MongoDB).
+type AchievementListFilter struct {
+	Statuses           []string
+	StudentID          string
+	AdvisorID          string
+	StudyProgram       string
+	CreatedFrom        *time.Time
+	CreatedTo          *time.Time
+	SubmittedFrom      *time.Time
+	SubmittedTo        *time.Time
+	MongoIDs           []string
+	RestrictToMongoIDs bool
+	Sort               string
+	Order              string
+	Page               int
+	Limit              int
+}
+
+// AchievementDocFilter adalah filter field yang hanya ada di dokumen MongoDB.
+type AchievementDocFilter struct {
+	AchievementType  string
+	CompetitionLevel string
+	MedalType        string
+	Tags             []string
+	EventFrom        *time.Time
+	EventTo          *time.Time
+	// Year mencocokkan tahun tanggal kegiatan, atau tanggal dibuat jika kosong.
+	Year int
+}
+
+func (f AchievementDocFilter) IsEmpty() bool {
+	return f.AchievementType == "" && f.CompetitionLevel == "" && f.MedalType == "" &&
+		len(f.Tags) == 0 && f.EventFrom == nil && f.EventTo == nil && f.Year == 0
+}
+
+type PageMeta struct {
+	Page       int  `json:"page"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+func NewPageMeta(page, limit, total int) PageMeta {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return PageMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// AchievementListMeta adalah metadata halaman daftar prestasi beserta
+// mongo_id yang dokumennya tidak ditemukan saat hidrasi.
+type AchievementListMeta struct {
+	PageMeta
+	MissingDocuments []string `json:"missing_documents,omitempty"`
+}
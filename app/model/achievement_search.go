@@ -0,0 +1,20 @@
+package model
+
+// SearchHighlight adalah potongan teks field yang cocok dengan kata kunci,
+// dengan kata yang cocok dibungkus <mark>.
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// AchievementSearchHit adalah id dokumen yang cocok beserta skor relevansinya.
+type AchievementSearchHit struct {
+	MongoID string
+	Score   float64
+}
+
+type AchievementSearchResult struct {
+	AchievementDetailResponse
+	Score      float64           `json:"score"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
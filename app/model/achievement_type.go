@@ -0,0 +1,40 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AchievementTypeDefinition struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Code        string             `bson:"code" json:"code"`
+	Version     int                `bson:"version" json:"version"`
+	Label       string             `bson:"label" json:"label"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	FieldLabels map[string]string  `bson:"fieldLabels,omitempty" json:"fieldLabels,omitempty"`
+	// Schema disimpan sebagai string JSON karena keyword seperti "$schema"
+	// tidak aman dipakai sebagai nama field MongoDB.
+	SchemaJSON    string          `bson:"detailsSchema" json:"-"`
+	DetailsSchema json.RawMessage `bson:"-" json:"detailsSchema"`
+	Evidence      EvidenceRule    `bson:"evidence" json:"evidence"`
+	IsActive      bool            `bson:"isActive" json:"isActive"`
+	CreatedBy     string          `bson:"createdBy" json:"createdBy"`
+	CreatedAt     time.Time       `bson:"createdAt" json:"createdAt"`
+}
+
+type EvidenceRule struct {
+	MinAttachments   int      `bson:"minAttachments" json:"minAttachments"`
+	AllowedFileTypes []string `bson:"allowedFileTypes,omitempty" json:"allowedFileTypes,omitempty"`
+	Description      string   `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+type SaveAchievementTypeRequest struct {
+	Code          string                 `json:"code"`
+	Label         string                 `json:"label"`
+	Description   string                 `json:"description,omitempty"`
+	FieldLabels   map[string]string      `json:"field_labels,omitempty"`
+	DetailsSchema map[string]interface{} `json:"details_schema"`
+	Evidence      EvidenceRule           `json:"evidence"`
+}
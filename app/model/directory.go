@@ -0,0 +1,17 @@
+package model
+
+// DirectoryFilter adalah filter daftar pengguna, mahasiswa, dan dosen. Field
+// yang tidak relevan untuk suatu daftar diabaikan.
+type DirectoryFilter struct {
+	Search       string
+	Role         string
+	IsActive     *bool
+	StudyProgram string
+	YearOfEntry  int
+	AdvisorID    string
+	Department   string
+	Sort         string
+	Order        string
+	Page         int
+	Limit        int
+}
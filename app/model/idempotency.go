@@ -0,0 +1,13 @@
+package model
+
+type IdempotencyRecord struct {
+	UserID       string
+	Key          string
+	Method       string
+	Path         string
+	RequestHash  string
+	StatusCode   *int
+	ContentType  string
+	Headers      map[string]string
+	ResponseBody []byte
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+const (
+	NotificationAchievementWithdrawn  = "achievement_withdrawn"
+	NotificationAchievementReopened   = "achievement_reopened"
+	NotificationSLAReminder           = "sla_reminder"
+	NotificationSLAEscalated          = "sla_escalated"
+	NotificationCertificationExpiring = "certification_expiring"
+	NotificationCertificationExpired  = "certification_expired"
+)
+
+type Notification struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Type        string    `json:"type"`
+	Title       string    `json:"title"`
+	Message     string    `json:"message"`
+	ReferenceID *string   `json:"reference_id,omitempty"`
+	IsRead      bool      `json:"is_read"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	OutboxAchievementCreate    = "achievement.create"
+	OutboxAchievementSetPoints = "achievement.set_points"
+)
+
+type OutboxEvent struct {
+	ID          string          `json:"id"`
+	EventType   string          `json:"event_type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastError   *string         `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// CreateAchievementPayload berisi dokumen MongoDB (Extended JSON) yang harus
+// dibuat untuk reference yang sudah tersimpan di PostgreSQL.
+type CreateAchievementPayload struct {
+	ReferenceID string          `json:"reference_id"`
+	MongoID     string          `json:"mongo_id"`
+	Document    json.RawMessage `json:"document"`
+}
+
+// SetPointsPayload menyimpan points hasil verifikasi satu reference ke
+// MongoDB. RefVersion dan Status adalah keadaan reference setelah transaksi
+// yang membuat event; event dilewati jika reference sudah berubah lagi. Jika
+// RevertOnFailure true, verifikasi dibatalkan saat points gagal disimpan.
+type SetPointsPayload struct {
+	ReferenceID     string `json:"reference_id"`
+	MongoID         string `json:"mongo_id"`
+	Points          int    `json:"points"`
+	RefVersion      int    `json:"ref_version"`
+	Status          string `json:"status"`
+	ActorID         string `json:"actor_id,omitempty"`
+	RevertOnFailure bool   `json:"revert_on_failure,omitempty"`
+}
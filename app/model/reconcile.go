@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+const (
+	ReconcileMissingDocument           = "missing_document"
+	ReconcileOrphanDocument            = "orphan_document"
+	ReconcileStudentMismatch           = "student_mismatch"
+	ReconcilePointsWithoutVerification = "points_without_verification"
+	ReconcileVerifiedWithoutPoints     = "verified_without_points"
+	ReconcileOrphanRevisions           = "orphan_revisions"
+)
+
+type ReconcileOptions struct {
+	Repair bool `json:"repair"`
+	DryRun bool `json:"dry_run"`
+}
+
+// ReferenceKey adalah ringkasan reference yang dibutuhkan untuk rekonsiliasi.
+type ReferenceKey struct {
+	ID        string
+	StudentID string
+	MongoID   string
+	Status    string
+	Version   int
+	UpdatedAt time.Time
+}
+
+type ReconcileIssue struct {
+	Kind        string `json:"kind"`
+	ReferenceID string `json:"reference_id,omitempty"`
+	MongoID     string `json:"mongo_id"`
+	StudentID   string `json:"student_id,omitempty"`
+	Detail      string `json:"detail"`
+	Action      string `json:"action,omitempty"`
+	Repaired    bool   `json:"repaired"`
+	Error       string `json:"error,omitempty"`
+}
+
+type ReconcileReport struct {
+	StartedAt         time.Time        `json:"started_at"`
+	FinishedAt        time.Time        `json:"finished_at"`
+	Repair            bool             `json:"repair"`
+	DryRun            bool             `json:"dry_run"`
+	ReferencesScanned int              `json:"references_scanned"`
+	DocumentsScanned  int              `json:"documents_scanned"`
+	SkippedInFlight   int              `json:"skipped_in_flight"`
+	Summary           map[string]int   `json:"summary"`
+	Issues            []ReconcileIssue `json:"issues"`
+}
@@ -0,0 +1,168 @@
+package model
+
+import "time"
+
+const (
+	ReportPeriodYear     = "year"
+	ReportPeriodSemester = "semester"
+	ReportPeriodMonth    = "month"
+)
+
+// ReportFilter adalah filter laporan statistik. From/To dibandingkan dengan
+// tanggal kegiatan prestasi, atau tanggal dibuat jika tanggal kegiatan kosong.
+type ReportFilter struct {
+	Statuses        []string
+	StudyProgram    string
+	YearOfEntry     int
+	AdvisorID       string
+	AchievementType string
+	From            *time.Time
+	To              *time.Time
+	Period          string
+}
+
+// ReportKey adalah data reference dari PostgreSQL yang digabung dengan
+// dokumen MongoDB saat menyusun laporan.
+type ReportKey struct {
+	ReferenceID  string
+	StudentID    string
+	StudentName  string
+	StudentNIM   string
+	MongoID      string
+	Status       string
+	StudyProgram string
+	YearOfEntry  int
+	AdvisorID    string
+	OptOut       bool
+	SubmittedAt  *time.Time
+	VerifiedAt   *time.Time
+}
+
+type StatBucket struct {
+	Key    string `json:"key"`
+	Count  int    `json:"count"`
+	Points int    `json:"points"`
+}
+
+type AchievementStatistics struct {
+	Period             string       `json:"period"`
+	Statuses           []string     `json:"statuses"`
+	Total              StatBucket   `json:"total"`
+	ByAchievementType  []StatBucket `json:"by_achievement_type"`
+	ByCompetitionLevel []StatBucket `json:"by_competition_level"`
+	ByStudyProgram     []StatBucket `json:"by_study_program"`
+	ByYearOfEntry      []StatBucket `json:"by_year_of_entry"`
+	ByPeriod           []StatBucket `json:"by_period"`
+	ByStatus           []StatBucket `json:"by_status"`
+	MissingDocuments   []string     `json:"missing_documents,omitempty"`
+	GeneratedAt        time.Time    `json:"generated_at"`
+}
+
+type StudentRank struct {
+	StudyProgram string `json:"study_program"`
+	Position     int    `json:"position"`
+	OutOf        int    `json:"out_of"`
+	Points       int    `json:"points"`
+}
+
+// StudentAchievementSummary adalah rapor prestasi satu mahasiswa. Rincian per
+// kategori, tingkat, dan semester hanya menghitung prestasi verified.
+type StudentAchievementSummary struct {
+	Student            StudentDetailResponse `json:"student"`
+	VerifiedCount      int                   `json:"verified_count"`
+	TotalPoints        int                   `json:"total_points"`
+	StatusCounts       map[string]int        `json:"status_counts"`
+	ByAchievementType  []StatBucket          `json:"by_achievement_type"`
+	ByCompetitionLevel []StatBucket          `json:"by_competition_level"`
+	PointsPerSemester  []StatBucket          `json:"points_per_semester"`
+	Rank               *StudentRank          `json:"rank,omitempty"`
+	MissingDocuments   []string              `json:"missing_documents,omitempty"`
+	GeneratedAt        time.Time             `json:"generated_at"`
+}
+
+const (
+	LeaderboardByStudyProgram = "study_program"
+	LeaderboardByYearOfEntry  = "year_of_entry"
+)
+
+type LeaderboardEntry struct {
+	Rank           int        `json:"rank"`
+	StudentID      string     `json:"student_id"`
+	NIM            string     `json:"nim"`
+	FullName       string     `json:"full_name"`
+	StudyProgram   string     `json:"study_program"`
+	YearOfEntry    int        `json:"year_of_entry,omitempty"`
+	Points         int        `json:"points"`
+	VerifiedCount  int        `json:"verified_count"`
+	LastVerifiedAt *time.Time `json:"last_verified_at,omitempty"`
+}
+
+type LeaderboardPartition struct {
+	Key     string             `json:"key"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+type Leaderboard struct {
+	PartitionBy string                 `json:"partition_by"`
+	From        *time.Time             `json:"from,omitempty"`
+	To          *time.Time             `json:"to,omitempty"`
+	TieBreakers []string               `json:"tie_breakers"`
+	Partitions  []LeaderboardPartition `json:"partitions"`
+	GeneratedAt time.Time              `json:"generated_at"`
+}
+
+type LeaderboardOptOutRequest struct {
+	OptOut *bool `json:"opt_out"`
+}
+
+type AdvisorWorkloadFilter struct {
+	LecturerID string
+	Department string
+	From       *time.Time
+	To         *time.Time
+	DueHours   int
+}
+
+// AdvisorWorkload merangkum beban dan kecepatan verifikasi satu dosen wali.
+// Durasi dalam jam, dihitung dari submit terakhir sampai keputusan dosen.
+type AdvisorWorkload struct {
+	ID                 string     `json:"id"`
+	LecturerID         string     `json:"lecturer_id"`
+	FullName           string     `json:"full_name"`
+	Department         string     `json:"department"`
+	AdviseeCount       int        `json:"advisee_count"`
+	PendingCount       int        `json:"pending_count"`
+	OverdueCount       int        `json:"overdue_count"`
+	OldestPendingAt    *time.Time `json:"oldest_pending_at,omitempty"`
+	DecidedCount       int        `json:"decided_count"`
+	RejectedCount      int        `json:"rejected_count"`
+	RejectionRate      float64    `json:"rejection_rate"`
+	AvgTurnaroundHours *float64   `json:"avg_turnaround_hours"`
+	P90TurnaroundHours *float64   `json:"p90_turnaround_hours"`
+}
+
+type OverdueItem struct {
+	ReferenceID  string     `json:"reference_id"`
+	StudentID    string     `json:"student_id"`
+	StudentName  string     `json:"student_name"`
+	StudentNIM   string     `json:"student_nim"`
+	SubmittedAt  time.Time  `json:"submitted_at"`
+	HoursWaiting int        `json:"hours_waiting"`
+	EscalatedAt  *time.Time `json:"escalated_at,omitempty"`
+}
+
+type AdvisorWorkloadReport struct {
+	From        *time.Time        `json:"from,omitempty"`
+	To          *time.Time        `json:"to,omitempty"`
+	SLAHours    int               `json:"sla_hours"`
+	Lecturers   []AdvisorWorkload `json:"lecturers"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+type AdvisorWorkloadDetail struct {
+	AdvisorWorkload
+	SLAHours     int                       `json:"sla_hours"`
+	Advisees     []LecturerAdviseeResponse `json:"advisees"`
+	OverdueItems []OverdueItem             `json:"overdue_items"`
+	GeneratedAt  time.Time                 `json:"generated_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+type BatchReviewItem struct {
+	ReferenceID string `json:"reference_id"`
+	Action      string `json:"action"`
+	Points      int    `json:"points,omitempty"`
+	Note        string `json:"note,omitempty"`
+	IfMatch     string `json:"if_match"`
+}
+
+type BatchReviewRequest struct {
+	Items []BatchReviewItem `json:"items"`
+}
+
+type BatchReviewResult struct {
+	ReferenceID            string `json:"reference_id"`
+	Action                 string `json:"action"`
+	Status                 string `json:"status"`
+	HTTPStatus             int    `json:"http_status"`
+	Error                  string `json:"error,omitempty"`
+	Points                 int    `json:"points,omitempty"`
+	TeamReferencesVerified int64  `json:"team_references_verified,omitempty"`
+}
+
+type BatchReviewResponse struct {
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []BatchReviewResult `json:"results"`
+}
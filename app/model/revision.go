@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RevisionReasonSubmit = "submit"
+	RevisionReasonEdit   = "edit"
+)
+
+type AchievementRevision struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AchievementID string             `bson:"achievementId" json:"achievementId"`
+	ReferenceID   string             `bson:"referenceId" json:"referenceId"`
+	Revision      int                `bson:"revision" json:"revision"`
+	Reason        string             `bson:"reason" json:"reason"`
+	Snapshot      *Achievement       `bson:"snapshot,omitempty" json:"snapshot,omitempty"`
+	CreatedBy     string             `bson:"createdBy" json:"createdBy"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Change string      `json:"change"`
+	From   interface{} `json:"from,omitempty"`
+	To     interface{} `json:"to,omitempty"`
+}
+
+type RevisionDiffResponse struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// SKPIEntry adalah reference verified yang dicantumkan di SKPI beserta nama
+// dosen yang memverifikasinya.
+type SKPIEntry struct {
+	ReferenceID  string
+	MongoID      string
+	VerifiedAt   time.Time
+	VerifierName string
+}
+
+// SKPIDocument adalah catatan setiap SKPI yang pernah diterbitkan.
+type SKPIDocument struct {
+	ID               string    `json:"id"`
+	DocumentNumber   string    `json:"document_number"`
+	StudentID        string    `json:"student_id"`
+	GeneratedBy      string    `json:"generated_by"`
+	ReferenceIDs     []string  `json:"reference_ids"`
+	AchievementCount int       `json:"achievement_count"`
+	TotalPoints      int       `json:"total_points"`
+	CreatedAt        time.Time `json:"created_at"`
+}
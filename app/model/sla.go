@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	SLAStateOnTrack   = "on_track"
+	SLAStateDueSoon   = "due_soon"
+	SLAStateOverdue   = "overdue"
+	SLAStateEscalated = "escalated"
+)
+
+type SLAStatus struct {
+	State       string     `json:"state"`
+	DueAt       time.Time  `json:"due_at"`
+	HoursLeft   int        `json:"hours_left"`
+	RemindedAt  *time.Time `json:"reminded_at,omitempty"`
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+}
+
+// SLACandidate adalah reference submitted yang perlu diingatkan atau dieskalasi.
+type SLACandidate struct {
+	ReferenceID  string
+	StudentID    string
+	StudentName  string
+	StudyProgram string
+	AdvisorID    string
+	SubmittedAt  time.Time
+}
+
+type EscalatedAchievement struct {
+	ReferenceID  string    `json:"reference_id"`
+	MongoID      string    `json:"mongo_id"`
+	StudentID    string    `json:"student_id"`
+	StudentName  string    `json:"student_name"`
+	StudyProgram string    `json:"study_program"`
+	AdvisorID    string    `json:"advisor_id,omitempty"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+	EscalatedAt  time.Time `json:"escalated_at"`
+}
@@ -17,6 +17,7 @@ type StudentListResponse struct {
 	StudentID    string  `json:"student_id"`
 	StudyProgram string  `json:"study_program"`
 	YearOfEntry  int     `json:"year_of_entry"`
+	AdvisorID    *string `json:"advisor_id,omitempty"`
 	AdvisorName  *string `json:"advisor_name,omitempty"`
 }
 
@@ -33,6 +34,8 @@ type StudentDetailResponse struct {
 type StudentAchievementsResponse struct {
 	Student      StudentDetailResponse       `json:"student"`
 	Achievements []AchievementDetailResponse `json:"achievements"`
+	// MissingDocuments berisi mongo_id yang dirujuk tetapi tidak ada di MongoDB.
+	MissingDocuments []string `json:"missing_documents,omitempty"`
 }
 
 type UpdateStudentAdvisorRequest struct {
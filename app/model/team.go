@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	TeamVerificationPerMember    = "per_member"
+	TeamVerificationOwnerAdvisor = "owner_advisor"
+)
+
+type TeamMember struct {
+	ID              string     `json:"id"`
+	MongoID         string     `json:"mongo_id"`
+	StudentID       string     `json:"student_id"`
+	StudentNIM      string     `json:"student_nim"`
+	FullName        string     `json:"full_name"`
+	InvitedBy       *string    `json:"invited_by,omitempty"`
+	Status          string     `json:"status"`
+	ReferenceID     *string    `json:"reference_id,omitempty"`
+	ReferenceStatus *string    `json:"reference_status,omitempty"`
+	InvitedAt       time.Time  `json:"invited_at"`
+	RespondedAt     *time.Time `json:"responded_at,omitempty"`
+}
+
+type TeamInvitationResponse struct {
+	TeamMember
+	Title           string `json:"title"`
+	AchievementType string `json:"achievement_type"`
+}
+
+type InviteTeamMembersRequest struct {
+	StudentIDs []string `json:"student_ids"`
+}
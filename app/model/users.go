@@ -40,6 +40,12 @@ type AssignRoleRequest struct {
     RoleName string `json:"role_name" validate:"required"`
 }
 
+// AssignStudyProgramsRequest menggantikan seluruh program studi yang
+// ditangani seorang admin. Daftar kosong berarti admin menangani semua program.
+type AssignStudyProgramsRequest struct {
+    StudyPrograms []string `json:"study_programs"`
+}
+
 type UserDetailResponse struct {
     ID        string `json:"id"`
     Username  string `json:"username"`
@@ -52,7 +58,9 @@ type UserListResponse struct {
     ID       string `json:"id"`
     Username string `json:"username"`
     FullName string `json:"full_name"`
+    Email    string `json:"email"`
     Role     string `json:"role"`
+    IsActive bool   `json:"is_active"`
 }
 
 func (u *User) ToUserResponse() UserResponse {
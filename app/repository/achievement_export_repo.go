@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"database/sql"
+
+	"go-fiber/app/model"
+)
+
+const achievementExportFrom = `
+        FROM achievement_references ar
+        JOIN students s ON ar.student_id = s.id
+        JOIN users su ON s.id = su.id
+        LEFT JOIN users adv ON s.advisor_id = adv.id
+        LEFT JOIN users ver ON ar.verified_by = ver.id
+    `
+
+// StreamExportRows menjalankan query daftar prestasi tanpa paginasi dan
+// mengirim hasilnya ke fn per batch, sehingga data tidak dimuat seluruhnya
+// ke memori.
+func (r *AchievementRefRepo) StreamExportRows(f model.AchievementListFilter, batchSize int, fn func([]model.AchievementExportRow) error) error {
+	from, args := listWhereFrom(achievementExportFrom, f)
+
+	rows, err := r.PG.Query(`
+        SELECT ar.id, ar.mongo_achievement_id, ar.status, s.student_id, su.full_name,
+               COALESCE(s.study_program, ''), COALESCE(s.year_of_entry, 0),
+               COALESCE(adv.full_name, ''), COALESCE(ver.full_name, ''), COALESCE(ar.rejection_note, ''),
+               ar.created_at, ar.submitted_at, ar.verified_at
+    `+from+listOrderBy(f), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	batch := make([]model.AchievementExportRow, 0, batchSize)
+	for rows.Next() {
+		var row model.AchievementExportRow
+		var submittedAt, verifiedAt sql.NullTime
+		if err := rows.Scan(&row.ReferenceID, &row.MongoID, &row.Status, &row.StudentNIM, &row.StudentName,
+			&row.StudyProgram, &row.YearOfEntry, &row.AdvisorName, &row.VerifierName, &row.RejectionNote,
+			&row.CreatedAt, &submittedAt, &verifiedAt); err != nil {
+			return err
+		}
+		if submittedAt.Valid {
+			row.SubmittedAt = &submittedAt.Time
+		}
+		if verifiedAt.Valid {
+			row.VerifiedAt = &verifiedAt.Time
+		}
+
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
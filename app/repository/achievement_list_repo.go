@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go-fiber/app/model"
+
+	"github.com/lib/pq"
+)
+
+// Kolom yang boleh dipakai untuk sorting daftar prestasi.
+var achievementListSortColumns = map[string]string{
+	"created_at":   "ar.created_at",
+	"updated_at":   "ar.updated_at",
+	"submitted_at": "ar.submitted_at",
+	"verified_at":  "ar.verified_at",
+	"status":       "ar.status",
+}
+
+func IsAchievementListSortable(field string) bool {
+	_, ok := achievementListSortColumns[field]
+	return ok
+}
+
+const achievementListFrom = `
+        FROM achievement_references ar
+        JOIN students s ON ar.student_id = s.id
+    `
+
+// listWhere menyusun klausa FROM/WHERE daftar reference beserta argumennya.
+func listWhere(f model.AchievementListFilter) (string, []interface{}) {
+	return listWhereFrom(achievementListFrom, f)
+}
+
+// listWhereFrom sama dengan listWhere tetapi memakai klausa FROM sendiri,
+// misalnya dengan join tambahan. Alias ar dan s wajib tersedia.
+func listWhereFrom(base string, f model.AchievementListFilter) (string, []interface{}) {
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(f.Statuses) > 0 {
+		where = append(where, "ar.status = ANY("+arg(pq.Array(f.Statuses))+")")
+	} else {
+		where = append(where, "ar.status != 'deleted'")
+	}
+	if f.StudentID != "" {
+		where = append(where, "ar.student_id = "+arg(f.StudentID))
+	}
+	if f.AdvisorID != "" {
+		where = append(where, "s.advisor_id = "+arg(f.AdvisorID))
+	}
+	if f.StudyProgram != "" {
+		where = append(where, "s.study_program = "+arg(f.StudyProgram))
+	}
+	if f.CreatedFrom != nil {
+		where = append(where, "ar.created_at >= "+arg(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		where = append(where, "ar.created_at < "+arg(*f.CreatedTo))
+	}
+	if f.SubmittedFrom != nil {
+		where = append(where, "ar.submitted_at >= "+arg(*f.SubmittedFrom))
+	}
+	if f.SubmittedTo != nil {
+		where = append(where, "ar.submitted_at < "+arg(*f.SubmittedTo))
+	}
+	if f.RestrictToMongoIDs {
+		where = append(where, "ar.mongo_achievement_id = ANY("+arg(pq.Array(f.MongoIDs))+")")
+	}
+
+	from := base
+	if len(where) > 0 {
+		from += " WHERE " + strings.Join(where, " AND ")
+	}
+	return from, args
+}
+
+func listOrderBy(f model.AchievementListFilter) string {
+	column, ok := achievementListSortColumns[f.Sort]
+	if !ok {
+		column = "ar.created_at"
+	}
+	order := "DESC NULLS LAST"
+	if f.Order == "asc" {
+		order = "ASC NULLS LAST"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, ar.id", column, order)
+}
+
+// ListReferences mengembalikan satu halaman reference sesuai filter beserta
+// jumlah total baris yang cocok.
+func (r *AchievementRefRepo) ListReferences(f model.AchievementListFilter) ([]model.AchievementDetailResponse, int, error) {
+	from, args := listWhere(f)
+	filterArgs := len(args)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	query := `
+        SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
+               ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
+               ar.created_at, ar.updated_at, ar.review_started_at, ar.deleted_at,
+               s.advisor_id, ar.sla_reminded_at, ar.escalated_at, ar.version,
+               COUNT(*) OVER ()
+    ` + from
+
+	query += listOrderBy(f)
+	query += " LIMIT " + arg(f.Limit) + " OFFSET " + arg((f.Page-1)*f.Limit)
+
+	rows, err := r.PG.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := []model.AchievementDetailResponse{}
+	total := 0
+	for rows.Next() {
+		var item model.AchievementDetailResponse
+		var submittedAt, verifiedAt, reviewStartedAt, deletedAt, slaRemindedAt, escalatedAt sql.NullTime
+		var verifiedBy, rejectionNote, advisorID sql.NullString
+
+		err := rows.Scan(
+			&item.ReferenceID,
+			&item.StudentID,
+			&item.MongoID,
+			&item.ReferenceStatus,
+			&submittedAt,
+			&verifiedAt,
+			&verifiedBy,
+			&rejectionNote,
+			&item.CreatedAtRef,
+			&item.UpdatedAtRef,
+			&reviewStartedAt,
+			&deletedAt,
+			&advisorID,
+			&slaRemindedAt,
+			&escalatedAt,
+			&item.Version,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		item.AdvisorID = advisorID.String
+		scanSLAMarkers(&item, slaRemindedAt, escalatedAt)
+		if submittedAt.Valid {
+			item.SubmittedAt = &submittedAt.Time
+		}
+		if verifiedAt.Valid {
+			item.VerifiedAt = &verifiedAt.Time
+		}
+		if reviewStartedAt.Valid {
+			item.ReviewStartedAt = &reviewStartedAt.Time
+		}
+		if deletedAt.Valid {
+			item.DeletedAt = &deletedAt.Time
+		}
+		if verifiedBy.Valid {
+			s := verifiedBy.String
+			item.VerifiedBy = &s
+		}
+		if rejectionNote.Valid {
+			s := rejectionNote.String
+			item.RejectionNote = &s
+		}
+
+		out = append(out, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Halaman di luar jangkauan tidak mengembalikan baris, jadi total dihitung terpisah.
+	if len(out) == 0 && f.Page > 1 {
+		if err := r.PG.QueryRow("SELECT COUNT(*) "+from, args[:filterArgs]...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return out, total, nil
+}
+
+// ListFacetKeys mengembalikan mongo_id, status, dan program studi seluruh
+// reference yang cocok dengan filter, tanpa paginasi.
+func (r *AchievementRefRepo) ListFacetKeys(f model.AchievementListFilter) ([]model.ReferenceFacetKey, error) {
+	from, args := listWhere(f)
+
+	rows, err := r.PG.Query("SELECT ar.mongo_achievement_id, ar.status, COALESCE(s.study_program, '') "+from, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.ReferenceFacetKey
+	for rows.Next() {
+		var k model.ReferenceFacetKey
+		if err := rows.Scan(&k.MongoID, &k.Status, &k.StudyProgram); err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
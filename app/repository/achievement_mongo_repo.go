@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"go-fiber/app/model"
@@ -9,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AchievementMongoRepo struct {
@@ -41,8 +43,36 @@ func (r *AchievementMongoRepo) UpdateByHexID(ctx context.Context, hexId string,
 		return err
 	}
 	update["updatedAt"] = time.Now()
-	_, err = r.Coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
-	return err
+	res, err := r.Coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateIfVersion hanya mengubah dokumen jika versinya masih sama dengan
+// expected, lalu menaikkan versi. Mengembalikan false jika versi sudah berubah.
+func (r *AchievementMongoRepo) UpdateIfVersion(ctx context.Context, hexId string, expected int, update bson.M) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(hexId)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"_id": oid, "version": expected}
+	if expected == 0 {
+		// Dokumen lama belum memiliki field version.
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	update["updatedAt"] = time.Now()
+	res, err := r.Coll.UpdateOne(ctx, filter, bson.M{"$set": update, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
 }
 
 func (r *AchievementMongoRepo) DeleteByHexID(ctx context.Context, hexId string) error {
@@ -75,6 +105,303 @@ func (r *AchievementMongoRepo) AddAttachments(ctx context.Context, hexId string,
 	for i := range atts {
 		atts[i].UploadedAt = time.Now()
 	}
-	_, err = r.Coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$push": bson.M{"attachments": bson.M{"$each": atts}}})
+	_, err = r.Coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$push": bson.M{"attachments": bson.M{"$each": atts}},
+		"$inc":  bson.M{"version": 1},
+	})
+	return err
+}
+
+func (r *AchievementMongoRepo) AddTeamMember(ctx context.Context, hexId, studentID string) error {
+	oid, err := primitive.ObjectIDFromHex(hexId)
+	if err != nil {
+		return err
+	}
+	_, err = r.Coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$addToSet": bson.M{"teamMembers": studentID},
+		"$set":      bson.M{"isTeam": true, "updatedAt": time.Now()},
+		"$inc":      bson.M{"version": 1},
+	})
+	return err
+}
+
+func (r *AchievementMongoRepo) RemoveTeamMember(ctx context.Context, hexId, studentID string) error {
+	oid, err := primitive.ObjectIDFromHex(hexId)
+	if err != nil {
+		return err
+	}
+	_, err = r.Coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$pull": bson.M{"teamMembers": studentID},
+		"$set":  bson.M{"updatedAt": time.Now()},
+		"$inc":  bson.M{"version": 1},
+	})
+	return err
+}
+
+// Restore memasukkan kembali dokumen yang sebelumnya dihapus (kompensasi).
+func (r *AchievementMongoRepo) Restore(ctx context.Context, ach model.Achievement) error {
+	_, err := r.Coll.InsertOne(ctx, ach)
 	return err
-}
\ No newline at end of file
+}
+
+func (r *AchievementMongoRepo) findCertifications(ctx context.Context, filter bson.M, limit int64) ([]model.Achievement, error) {
+	filter["achievementType"] = model.AchievementTypeCertification
+	cur, err := r.Coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "details.validUntil", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []model.Achievement
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListCertificationsToWarn mengembalikan sertifikasi yang akan kedaluwarsa
+// sebelum warnBefore dan belum pernah diperingatkan.
+func (r *AchievementMongoRepo) ListCertificationsToWarn(ctx context.Context, now, warnBefore time.Time, limit int64) ([]model.Achievement, error) {
+	return r.findCertifications(ctx, bson.M{
+		"details.validUntil": bson.M{"$gt": now, "$lte": warnBefore},
+		"expiryWarnedAt":     nil,
+		"expiredAt":          nil,
+	}, limit)
+}
+
+// ListCertificationsToExpire mengembalikan sertifikasi yang masa berlakunya
+// sudah lewat tetapi belum ditandai expired.
+func (r *AchievementMongoRepo) ListCertificationsToExpire(ctx context.Context, now time.Time, limit int64) ([]model.Achievement, error) {
+	return r.findCertifications(ctx, bson.M{
+		"details.validUntil": bson.M{"$lte": now},
+		"expiredAt":          nil,
+	}, limit)
+}
+
+func (r *AchievementMongoRepo) MarkExpiryWarned(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.Coll.UpdateOne(ctx, bson.M{"_id": id, "expiryWarnedAt": nil}, bson.M{"$set": bson.M{"expiryWarnedAt": at}})
+	return err
+}
+
+// MarkExpired menandai sertifikasi kedaluwarsa. Mengembalikan false jika
+// dokumen sudah ditandai oleh proses lain.
+func (r *AchievementMongoRepo) MarkExpired(ctx context.Context, id primitive.ObjectID, at time.Time, excludePoints bool) (bool, error) {
+	res, err := r.Coll.UpdateOne(ctx, bson.M{"_id": id, "expiredAt": nil}, bson.M{"$set": bson.M{
+		"expiredAt":      at,
+		"pointsExcluded": excludePoints,
+		"updatedAt":      at,
+	}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// InsertWithID membuat dokumen dengan _id yang sudah ditentukan. Dokumen yang
+// sudah ada dianggap berhasil agar aman dijalankan ulang oleh worker outbox.
+func (r *AchievementMongoRepo) InsertWithID(ctx context.Context, ach model.Achievement) error {
+	_, err := r.Coll.InsertOne(ctx, ach)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// ListForReconcile mengembalikan semua dokumen dengan field yang dibutuhkan
+// untuk rekonsiliasi saja.
+func (r *AchievementMongoRepo) ListForReconcile(ctx context.Context) ([]model.Achievement, error) {
+	projection := bson.M{"_id": 1, "studentId": 1, "teamMembers": 1, "points": 1, "referencePoints": 1, "createdAt": 1}
+	cur, err := r.Coll.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []model.Achievement
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// achievementYearExpr adalah tahun prestasi: tanggal kegiatan jika ada,
+// selain itu tanggal dokumen dibuat.
+var achievementYearExpr = bson.M{"$year": bson.M{"$ifNull": bson.A{"$details.eventDate", "$createdAt"}}}
+
+func docFilterQuery(f model.AchievementDocFilter) bson.M {
+	filter := bson.M{}
+	if f.AchievementType != "" {
+		filter["achievementType"] = f.AchievementType
+	}
+	if f.CompetitionLevel != "" {
+		filter["details.competitionLevel"] = f.CompetitionLevel
+	}
+	if f.MedalType != "" {
+		filter["details.medalType"] = f.MedalType
+	}
+	if len(f.Tags) > 0 {
+		filter["tags"] = bson.M{"$all": f.Tags}
+	}
+	if f.EventFrom != nil || f.EventTo != nil {
+		eventDate := bson.M{}
+		if f.EventFrom != nil {
+			eventDate["$gte"] = *f.EventFrom
+		}
+		if f.EventTo != nil {
+			eventDate["$lt"] = *f.EventTo
+		}
+		filter["details.eventDate"] = eventDate
+	}
+	if f.Year != 0 {
+		filter["$expr"] = bson.M{"$eq": bson.A{achievementYearExpr, f.Year}}
+	}
+	return filter
+}
+
+// FindIDs mengembalikan id dokumen yang cocok dengan filter field MongoDB,
+// dipakai sebagai prefilter sebelum query PostgreSQL.
+func (r *AchievementMongoRepo) FindIDs(ctx context.Context, f model.AchievementDocFilter) ([]string, error) {
+	filter := docFilterQuery(f)
+
+	cur, err := r.Coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	ids := []string{}
+	for cur.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID.Hex())
+	}
+	return ids, cur.Err()
+}
+
+// FindByHexIDs mengambil banyak dokumen sekaligus dengan satu query $in.
+// Hasil dikembalikan sebagai map hex id -> dokumen; id yang tidak valid atau
+// tidak ditemukan tidak ada di map. Projection nil berarti seluruh field.
+func (r *AchievementMongoRepo) FindByHexIDs(ctx context.Context, hexIDs []string, projection bson.M) (map[string]model.Achievement, error) {
+	out := make(map[string]model.Achievement, len(hexIDs))
+
+	oids := make([]primitive.ObjectID, 0, len(hexIDs))
+	seen := make(map[primitive.ObjectID]bool, len(hexIDs))
+	for _, h := range hexIDs {
+		oid, err := primitive.ObjectIDFromHex(h)
+		if err != nil || seen[oid] {
+			continue
+		}
+		seen[oid] = true
+		oids = append(oids, oid)
+	}
+	if len(oids) == 0 {
+		return out, nil
+	}
+
+	opts := options.Find()
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	cur, err := r.Coll.Find(ctx, bson.M{"_id": bson.M{"$in": oids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var doc model.Achievement
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		out[doc.ID.Hex()] = doc
+	}
+	return out, cur.Err()
+}
+
+// SearchText menjalankan pencarian pada text index achievement_text. Hanya id
+// dan skor yang dikembalikan, terurut berdasarkan relevansi; hak akses
+// disaring oleh pemanggil.
+func (r *AchievementMongoRepo) SearchText(ctx context.Context, query string) ([]model.AchievementSearchHit, error) {
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1, "score": score}).
+		SetSort(bson.M{"score": score})
+
+	cur, err := r.Coll.Find(ctx, bson.M{"$text": bson.M{"$search": query}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []model.AchievementSearchHit{}
+	for cur.Next(ctx) {
+		var doc struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Score float64            `bson:"score"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		out = append(out, model.AchievementSearchHit{MongoID: doc.ID.Hex(), Score: doc.Score})
+	}
+	return out, cur.Err()
+}
+
+// FacetFields mengambil nilai dimensi facet (tipe, tingkat, medali, tahun)
+// untuk dokumen dengan id yang diberikan, sebagai map hex id -> nilai.
+func (r *AchievementMongoRepo) FacetFields(ctx context.Context, hexIDs []string) (map[string]model.DocumentFacetFields, error) {
+	out := make(map[string]model.DocumentFacetFields, len(hexIDs))
+
+	oids := make([]primitive.ObjectID, 0, len(hexIDs))
+	for _, h := range hexIDs {
+		if oid, err := primitive.ObjectIDFromHex(h); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return out, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": oids}}}},
+		{{Key: "$project", Value: bson.M{
+			"achievementType":  1,
+			"competitionLevel": "$details.competitionLevel",
+			"medalType":        "$details.medalType",
+			"year":             achievementYearExpr,
+		}}},
+	}
+
+	cur, err := r.Coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var doc struct {
+			ID               primitive.ObjectID `bson:"_id"`
+			AchievementType  string             `bson:"achievementType"`
+			CompetitionLevel string             `bson:"competitionLevel"`
+			MedalType        string             `bson:"medalType"`
+			Year             int                `bson:"year"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		fields := model.DocumentFacetFields{
+			AchievementType:  doc.AchievementType,
+			CompetitionLevel: doc.CompetitionLevel,
+			MedalType:        doc.MedalType,
+		}
+		if doc.Year > 0 {
+			fields.Year = strconv.Itoa(doc.Year)
+		}
+		out[doc.ID.Hex()] = fields
+	}
+	return out, cur.Err()
+}
@@ -13,20 +13,108 @@ func NewAchievementRefRepo(pg *sql.DB) *AchievementRefRepo {
 	return &AchievementRefRepo{PG: pg}
 }
 
-func (r *AchievementRefRepo) CreateReference(studentID, mongoHex string) (string, error) {
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertStatusHistory(exec sqlExecutor, refID, fromStatus, toStatus, actorID, note string) error {
+	_, err := exec.Exec(`
+        INSERT INTO achievement_status_history (reference_id, from_status, to_status, actor_id, note)
+        VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, '')::uuid, NULLIF($5, ''))
+    `, refID, fromStatus, toStatus, actorID, note)
+	return err
+}
+
+func scanSLAMarkers(out *model.AchievementDetailResponse, remindedAt, escalatedAt sql.NullTime) {
+	if remindedAt.Valid {
+		out.SLARemindedAt = &remindedAt.Time
+	}
+	if escalatedAt.Valid {
+		out.EscalatedAt = &escalatedAt.Time
+	}
+}
+
+// CreateReference membuat reference draft di dalam transaksi pemanggil.
+func (r *AchievementRefRepo) CreateReference(tx *sql.Tx, studentID, mongoHex string) (string, error) {
 	var id string
-	err := r.PG.QueryRow(`
+	err := tx.QueryRow(`
         INSERT INTO achievement_references 
         (student_id, mongo_achievement_id, status, created_at, updated_at)
         VALUES ($1, $2, 'draft', NOW(), NOW())
         RETURNING id
     `, studentID, mongoHex).Scan(&id)
-	return id, err
+	if err != nil {
+		return "", err
+	}
+
+	if err := insertStatusHistory(tx, id, "", "draft", studentID, ""); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// DeleteOrphanReference menghapus reference draft/deleted yang dokumen
+// MongoDB-nya tidak ada (kompensasi outbox dan rekonsiliasi).
+func (r *AchievementRefRepo) DeleteOrphanReference(refID, mongoHex string) error {
+	_, err := r.PG.Exec(`
+        DELETE FROM achievement_references
+        WHERE id = $1 AND mongo_achievement_id = $2 AND status IN ('draft', 'deleted')
+    `, refID, mongoHex)
+	return err
+}
+
+// ListReferenceKeys mengembalikan semua reference untuk dicocokkan dengan MongoDB.
+func (r *AchievementRefRepo) ListReferenceKeys() ([]model.ReferenceKey, error) {
+	rows, err := r.PG.Query(`
+        SELECT id, student_id, mongo_achievement_id, status, version, updated_at
+        FROM achievement_references
+        ORDER BY created_at
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.ReferenceKey
+	for rows.Next() {
+		var k model.ReferenceKey
+		if err := rows.Scan(&k.ID, &k.StudentID, &k.MongoID, &k.Status, &k.Version, &k.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+// RevertVerification mengembalikan satu reference verified ke submitted jika
+// points di MongoDB tidak berhasil disimpan (kompensasi outbox dan
+// rekonsiliasi). Jika version bukan 0, reference hanya dikembalikan selama
+// versinya belum berubah. Mengembalikan false jika tidak ada yang diubah.
+func (r *AchievementRefRepo) RevertVerification(refID string, version int, actorID, note string) (bool, error) {
+	res, err := r.PG.Exec(`
+        WITH updated AS (
+            UPDATE achievement_references
+            SET status = 'submitted', submitted_at = NOW(), verified_at = NULL, verified_by = NULL,
+                review_started_at = NULL, review_started_by = NULL, sla_reminded_at = NULL, escalated_at = NULL,
+                updated_at = NOW()
+            WHERE id = $1 AND status = 'verified' AND ($2 = 0 OR version = $2)
+            RETURNING id
+        )
+        INSERT INTO achievement_status_history (reference_id, from_status, to_status, actor_id, note)
+        SELECT id, 'verified', 'submitted', NULLIF($3, '')::uuid, $4
+        FROM updated
+    `, refID, version, actorID, note)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
 }
 
 func (r *AchievementRefRepo) GetReference(refID string) (*model.AchievementDetailResponse, error) {
 	var out model.AchievementDetailResponse
-	var submittedAt, verifiedAt sql.NullTime
+	var submittedAt, verifiedAt, reviewStartedAt, deletedAt, slaRemindedAt, escalatedAt sql.NullTime
 	var verifiedBy, rejectionNote sql.NullString
 
 	var mongoHex, studentID string
@@ -34,7 +122,8 @@ func (r *AchievementRefRepo) GetReference(refID string) (*model.AchievementDetai
 	err := r.PG.QueryRow(`
         SELECT id, student_id, mongo_achievement_id, status,
                submitted_at, verified_at, verified_by, rejection_note,
-               created_at, updated_at
+               created_at, updated_at, review_started_at, deleted_at,
+               sla_reminded_at, escalated_at, version
         FROM achievement_references
         WHERE id = $1
     `, refID).Scan(
@@ -48,6 +137,11 @@ func (r *AchievementRefRepo) GetReference(refID string) (*model.AchievementDetai
 		&rejectionNote,
 		&out.CreatedAtRef,
 		&out.UpdatedAtRef,
+		&reviewStartedAt,
+		&deletedAt,
+		&slaRemindedAt,
+		&escalatedAt,
+		&out.Version,
 	)
 
 	if err != nil {
@@ -56,6 +150,13 @@ func (r *AchievementRefRepo) GetReference(refID string) (*model.AchievementDetai
 
 	out.StudentID = studentID
 	out.MongoID = mongoHex
+	if reviewStartedAt.Valid {
+		out.ReviewStartedAt = &reviewStartedAt.Time
+	}
+	if deletedAt.Valid {
+		out.DeletedAt = &deletedAt.Time
+	}
+	scanSLAMarkers(&out, slaRemindedAt, escalatedAt)
 
 	if submittedAt.Valid {
 		out.SubmittedAt = &submittedAt.Time
@@ -77,15 +178,15 @@ func (r *AchievementRefRepo) GetReference(refID string) (*model.AchievementDetai
 
 func (r *AchievementRefRepo) GetReferenceDetail(refID string) (*model.AchievementDetailResponse, error) {
 	var out model.AchievementDetailResponse
-	var submittedAt, verifiedAt sql.NullTime
-	var verifiedBy, rejectionNote sql.NullString
-	var mongoHex, studentID, advisorID string
+	var submittedAt, verifiedAt, reviewStartedAt, slaRemindedAt, escalatedAt sql.NullTime
+	var verifiedBy, rejectionNote, advisorID sql.NullString
+	var mongoHex, studentID string
 
 	err := r.PG.QueryRow(`
         SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
                ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
-               ar.created_at, ar.updated_at,
-               s.advisor_id
+               ar.created_at, ar.updated_at, ar.review_started_at,
+               s.advisor_id, ar.sla_reminded_at, ar.escalated_at, ar.version
         FROM achievement_references ar
         JOIN students s ON ar.student_id = s.id
         WHERE ar.id = $1
@@ -100,7 +201,11 @@ func (r *AchievementRefRepo) GetReferenceDetail(refID string) (*model.Achievemen
 		&rejectionNote,
 		&out.CreatedAtRef,
 		&out.UpdatedAtRef,
+		&reviewStartedAt,
 		&advisorID,
+		&slaRemindedAt,
+		&escalatedAt,
+		&out.Version,
 	)
 
 	if err != nil {
@@ -109,7 +214,11 @@ func (r *AchievementRefRepo) GetReferenceDetail(refID string) (*model.Achievemen
 
 	out.StudentID = studentID
 	out.MongoID = mongoHex
-	out.AdvisorID = advisorID
+	out.AdvisorID = advisorID.String
+	if reviewStartedAt.Valid {
+		out.ReviewStartedAt = &reviewStartedAt.Time
+	}
+	scanSLAMarkers(&out, slaRemindedAt, escalatedAt)
 
 	if submittedAt.Valid {
 		out.SubmittedAt = &submittedAt.Time
@@ -134,13 +243,14 @@ func (r *AchievementRefRepo) GetReferenceWithAdvisor(refID, advisorID string) (*
 	var submittedAt, verifiedAt sql.NullTime
 	var verifiedBy, rejectionNote sql.NullString
 
-	var mongoHex, studentID, retrievedAdvisorID string
+	var retrievedAdvisorID sql.NullString
+	var mongoHex, studentID string
 
 	err := r.PG.QueryRow(`
         SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status,
                ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
                ar.created_at, ar.updated_at,
-               s.advisor_id
+               s.advisor_id, ar.version
         FROM achievement_references ar
         JOIN students s ON ar.student_id = s.id
         WHERE ar.id = $1
@@ -156,6 +266,7 @@ func (r *AchievementRefRepo) GetReferenceWithAdvisor(refID, advisorID string) (*
 		&out.CreatedAtRef,
 		&out.UpdatedAtRef,
 		&retrievedAdvisorID,
+		&out.Version,
 	)
 
 	if err != nil {
@@ -164,7 +275,7 @@ func (r *AchievementRefRepo) GetReferenceWithAdvisor(refID, advisorID string) (*
 
 	out.StudentID = studentID
 	out.MongoID = mongoHex
-	out.AdvisorID = retrievedAdvisorID
+	out.AdvisorID = retrievedAdvisorID.String
 
 	if submittedAt.Valid {
 		out.SubmittedAt = &submittedAt.Time
@@ -184,214 +295,334 @@ func (r *AchievementRefRepo) GetReferenceWithAdvisor(refID, advisorID string) (*
 	return &out, nil
 }
 
-func (r *AchievementRefRepo) SubmitReference(refID string) error {
-	_, err := r.PG.Exec(`
+func (r *AchievementRefRepo) SubmitReference(tx *sql.Tx, refID, fromStatus, actorID string) error {
+	_, err := tx.Exec(`
         UPDATE achievement_references
-        SET status = 'submitted', submitted_at = NOW(), updated_at = NOW()
+        SET status = 'submitted', submitted_at = NOW(), review_started_at = NULL, review_started_by = NULL,
+            sla_reminded_at = NULL, escalated_at = NULL, updated_at = NOW()
         WHERE id = $1
     `, refID)
-	return err
+	if err != nil {
+		return err
+	}
+	return insertStatusHistory(tx, refID, fromStatus, "submitted", actorID, "")
+}
+
+// WithdrawReference mengembalikan reference submitted ke draft selama belum
+// ada dosen yang mulai mereview. Mengembalikan false jika syarat tidak terpenuhi.
+func (r *AchievementRefRepo) WithdrawReference(tx *sql.Tx, refID, actorID string) (bool, error) {
+	res, err := tx.Exec(`
+        UPDATE achievement_references
+        SET status = 'draft', submitted_at = NULL, updated_at = NOW()
+        WHERE id = $1 AND status = 'submitted' AND review_started_at IS NULL
+    `, refID)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	return true, insertStatusHistory(tx, refID, "submitted", "draft", actorID, "Ditarik kembali oleh mahasiswa")
 }
 
-func (r *AchievementRefRepo) VerifyReference(refID, verifierID string) error {
+// MarkReviewStarted mencatat bahwa reviewer mulai mereview prestasi yang
+// disubmit. Review yang sudah dimulai tidak diubah.
+func (r *AchievementRefRepo) MarkReviewStarted(refID, reviewerID string) error {
 	_, err := r.PG.Exec(`
         UPDATE achievement_references
+        SET review_started_at = NOW(), review_started_by = $1
+        WHERE id = $2 AND status = 'submitted' AND review_started_at IS NULL
+    `, reviewerID, refID)
+	return err
+}
+
+// LockReferenceStatus mengunci baris reference sampai transaksi selesai dan
+// mengembalikan status terkininya.
+func (r *AchievementRefRepo) LockReferenceStatus(tx *sql.Tx, refID string) (string, error) {
+	var status string
+	err := tx.QueryRow(`
+        SELECT status FROM achievement_references WHERE id = $1 FOR UPDATE
+    `, refID).Scan(&status)
+	return status, err
+}
+
+// LockReference seperti LockReferenceStatus, tetapi juga mengembalikan versi
+// reference untuk pemeriksaan If-Match.
+func (r *AchievementRefRepo) LockReference(tx *sql.Tx, refID string) (string, int, error) {
+	var status string
+	var version int
+	err := tx.QueryRow(`
+        SELECT status, version FROM achievement_references WHERE id = $1 FOR UPDATE
+    `, refID).Scan(&status, &version)
+	return status, version, err
+}
+
+// VerifyReference memverifikasi reference dan mengembalikan versi barunya.
+func (r *AchievementRefRepo) VerifyReference(tx *sql.Tx, refID, verifierID string) (int, error) {
+	var version int
+	err := tx.QueryRow(`
+        UPDATE achievement_references
         SET status = 'verified', verified_at = NOW(), verified_by = $1, rejection_note = NULL, updated_at = NOW()
         WHERE id = $2
-    `, verifierID, refID)
-	return err
+        RETURNING version
+    `, verifierID, refID).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version, insertStatusHistory(tx, refID, "submitted", "verified", verifierID, "")
 }
 
-func (r *AchievementRefRepo) RejectReference(refID, verifierID, note string) error {
-	_, err := r.PG.Exec(`
+func (r *AchievementRefRepo) RejectReference(tx *sql.Tx, refID, verifierID, note string) error {
+	_, err := tx.Exec(`
         UPDATE achievement_references
         SET status = 'rejected', verified_at = NOW(), verified_by = $1, rejection_note = $2, updated_at = NOW()
         WHERE id = $3
     `, verifierID, note, refID)
-	return err
+	if err != nil {
+		return err
+	}
+	return insertStatusHistory(tx, refID, "submitted", "rejected", verifierID, note)
 }
 
-func (r *AchievementRefRepo) SoftDeleteReference(refID string) error {
-	_, err := r.PG.Exec(`
+func (r *AchievementRefRepo) SoftDeleteReference(tx *sql.Tx, refID, fromStatus, actorID string) error {
+	_, err := tx.Exec(`
         UPDATE achievement_references
-        SET status = 'deleted', updated_at = NOW()
+        SET status = 'deleted', deleted_at = NOW(), deleted_from_status = $2, updated_at = NOW()
         WHERE id = $1
-    `, refID)
-	return err
+    `, refID, fromStatus)
+	if err != nil {
+		return err
+	}
+	return insertStatusHistory(tx, refID, fromStatus, "deleted", actorID, "")
 }
 
-func (r *AchievementRefRepo) ListForStudent(studentID string) ([]model.AchievementDetailResponse, error) {
+// RestoreReference mengembalikan reference yang dihapus ke status sebelum
+// dihapus selama masih dalam masa tenggang.
+func (r *AchievementRefRepo) RestoreReference(tx *sql.Tx, refID, actorID string, graceDays int) (bool, error) {
+	var restored string
+	err := tx.QueryRow(`
+        UPDATE achievement_references
+        SET status = COALESCE(deleted_from_status, 'draft'), deleted_at = NULL, deleted_from_status = NULL, updated_at = NOW()
+        WHERE id = $1 AND status = 'deleted'
+          AND COALESCE(deleted_at, updated_at) >= NOW() - make_interval(days => $2)
+        RETURNING status
+    `, refID, graceDays).Scan(&restored)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, insertStatusHistory(tx, refID, "deleted", restored, actorID, "Dipulihkan")
+}
+
+// ListDeletedBefore mengembalikan id reference berstatus deleted yang dihapus
+// sebelum batas waktu tertentu.
+func (r *AchievementRefRepo) ListDeletedBefore(days, limit int) ([]string, error) {
 	rows, err := r.PG.Query(`
-        SELECT id, mongo_achievement_id, status,
-               submitted_at, verified_at, verified_by, rejection_note,
-               created_at, updated_at
-        FROM achievement_references
-        WHERE student_id = $1 AND status != 'deleted'
-        ORDER BY created_at DESC
-    `, studentID)
+        SELECT id FROM achievement_references
+        WHERE status = 'deleted' AND COALESCE(deleted_at, updated_at) < NOW() - make_interval(days => $1)
+        ORDER BY COALESCE(deleted_at, updated_at)
+        LIMIT $2
+    `, days, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := []model.AchievementDetailResponse{}
+	var ids []string
 	for rows.Next() {
-		var item model.AchievementDetailResponse
-		var submittedAt, verifiedAt sql.NullTime
-		var verifiedBy, rejectionNote sql.NullString
-		var mongoHex string
-
-		err := rows.Scan(
-			&item.ReferenceID,
-			&mongoHex,
-			&item.ReferenceStatus,
-			&submittedAt,
-			&verifiedAt,
-			&verifiedBy,
-			&rejectionNote,
-			&item.CreatedAtRef,
-			&item.UpdatedAtRef,
-		)
-		if err != nil {
+		var id string
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
 
-		item.MongoID = mongoHex
+// DeleteReference menghapus permanen reference yang berstatus deleted dan
+// mengembalikan jumlah reference lain yang masih memakai dokumen MongoDB yang sama.
+func (r *AchievementRefRepo) DeleteReference(tx *sql.Tx, refID, mongoHex string) (int, error) {
+	res, err := tx.Exec(`DELETE FROM achievement_references WHERE id = $1 AND status = 'deleted'`, refID)
+	if err != nil {
+		return 0, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, sql.ErrNoRows
+	}
 
-		if submittedAt.Valid {
-			item.SubmittedAt = &submittedAt.Time
-		}
-		if verifiedAt.Valid {
-			item.VerifiedAt = &verifiedAt.Time
-		}
-		if verifiedBy.Valid {
-			s := verifiedBy.String
-			item.VerifiedBy = &s
-		}
-		if rejectionNote.Valid {
-			s := rejectionNote.String
-			item.RejectionNote = &s
-		}
+	var remaining int
+	err = tx.QueryRow(`
+        SELECT COUNT(*) FROM achievement_references WHERE mongo_achievement_id = $1
+    `, mongoHex).Scan(&remaining)
+	if err != nil {
+		return 0, err
+	}
 
-		out = append(out, item)
+	if remaining == 0 {
+		_, err = tx.Exec(`DELETE FROM achievement_team_members WHERE mongo_achievement_id = $1`, mongoHex)
 	}
-	return out, nil
+	return remaining, err
 }
 
-func (r *AchievementRefRepo) ListForAdvisor(advisorID string) ([]model.AchievementDetailResponse, error) {
+func (r *AchievementRefRepo) ListStatusHistory(refID string) ([]model.StatusHistoryEntry, error) {
 	rows, err := r.PG.Query(`
-        SELECT ar.id, ar.mongo_achievement_id, ar.status,
-               ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
-               ar.created_at, ar.updated_at
-        FROM achievement_references ar
-        JOIN students s ON ar.student_id = s.id
-        WHERE s.advisor_id = $1 AND ar.status != 'deleted'
-        ORDER BY ar.created_at DESC
-    `, advisorID)
+        SELECT h.id, h.from_status, h.to_status, h.actor_id, u.full_name, h.note, h.created_at
+        FROM achievement_status_history h
+        LEFT JOIN users u ON h.actor_id = u.id
+        WHERE h.reference_id = $1
+        ORDER BY h.created_at, h.id
+    `, refID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := []model.AchievementDetailResponse{}
+	out := []model.StatusHistoryEntry{}
 	for rows.Next() {
-		var item model.AchievementDetailResponse
-		var submittedAt, verifiedAt sql.NullTime
-		var verifiedBy, rejectionNote sql.NullString
-		var mongoHex string
-
-		err := rows.Scan(
-			&item.ReferenceID,
-			&mongoHex,
-			&item.ReferenceStatus,
-			&submittedAt,
-			&verifiedAt,
-			&verifiedBy,
-			&rejectionNote,
-			&item.CreatedAtRef,
-			&item.UpdatedAtRef,
-		)
-		if err != nil {
+		var e model.StatusHistoryEntry
+		var fromStatus, actorID, actorName, note sql.NullString
+		if err := rows.Scan(&e.ID, &fromStatus, &e.ToStatus, &actorID, &actorName, &note, &e.CreatedAt); err != nil {
 			return nil, err
 		}
-
-		item.MongoID = mongoHex
-
-		if submittedAt.Valid {
-			item.SubmittedAt = &submittedAt.Time
+		if fromStatus.Valid {
+			s := fromStatus.String
+			e.FromStatus = &s
 		}
-		if verifiedAt.Valid {
-			item.VerifiedAt = &verifiedAt.Time
+		if actorID.Valid {
+			s := actorID.String
+			e.ActorID = &s
 		}
-		if verifiedBy.Valid {
-			s := verifiedBy.String
-			item.VerifiedBy = &s
+		if actorName.Valid {
+			s := actorName.String
+			e.ActorName = &s
 		}
-		if rejectionNote.Valid {
-			s := rejectionNote.String
-			item.RejectionNote = &s
+		if note.Valid {
+			s := note.String
+			e.Note = &s
 		}
-
-		out = append(out, item)
+		out = append(out, e)
 	}
-
 	return out, nil
 }
 
-func (r *AchievementRefRepo) ListForAdmin() ([]model.AchievementDetailResponse, error) {
-	rows, err := r.PG.Query(`
-        SELECT id, mongo_achievement_id, status,
-               submitted_at, verified_at, verified_by, rejection_note,
-               created_at, updated_at
-        FROM achievement_references
-        ORDER BY created_at DESC
-    `)
+// VerifyTeamReferences memverifikasi reference anggota tim lain yang berbagi
+// dokumen MongoDB yang sama (mode verifikasi owner_advisor). Hanya anggota yang
+// sudah konfirmasi dan reference-nya berstatus submitted yang ikut diverifikasi.
+// Mengembalikan reference yang diverifikasi beserta versi barunya.
+func (r *AchievementRefRepo) VerifyTeamReferences(tx *sql.Tx, mongoHex, excludeRefID, verifierID string) ([]model.ReferenceVersion, error) {
+	rows, err := tx.Query(`
+        WITH updated AS (
+            UPDATE achievement_references ar
+            SET status = 'verified', verified_at = NOW(), verified_by = $1, rejection_note = NULL, updated_at = NOW()
+            FROM achievement_team_members tm
+            WHERE tm.reference_id = ar.id AND tm.status = 'confirmed'
+              AND ar.mongo_achievement_id = $2 AND ar.id != $3 AND ar.status = 'submitted'
+            RETURNING ar.id, ar.version
+        ), history AS (
+            INSERT INTO achievement_status_history (reference_id, from_status, to_status, actor_id, note)
+            SELECT id, 'submitted', 'verified', $1, 'Diverifikasi bersama prestasi tim'
+            FROM updated
+        )
+        SELECT id, version FROM updated
+    `, verifierID, mongoHex, excludeRefID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := []model.AchievementDetailResponse{}
+	var out []model.ReferenceVersion
 	for rows.Next() {
-		var item model.AchievementDetailResponse
-		var submittedAt, verifiedAt sql.NullTime
-		var verifiedBy, rejectionNote sql.NullString
-		var mongoHex string
-
-		err := rows.Scan(
-			&item.ReferenceID,
-			&mongoHex,
-			&item.ReferenceStatus,
-			&submittedAt,
-			&verifiedAt,
-			&verifiedBy,
-			&rejectionNote,
-			&item.CreatedAtRef,
-			&item.UpdatedAtRef,
-		)
-		if err != nil {
+		var item model.ReferenceVersion
+		if err := rows.Scan(&item.ID, &item.Version); err != nil {
 			return nil, err
 		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
 
-		item.MongoID = mongoHex
+// RejectTeamReferences menolak reference submitted milik anggota tim yang
+// sudah konfirmasi (mode verifikasi owner_advisor).
+func (r *AchievementRefRepo) RejectTeamReferences(tx *sql.Tx, mongoHex, excludeRefID, verifierID, note string) (int64, error) {
+	res, err := tx.Exec(`
+        WITH updated AS (
+            UPDATE achievement_references ar
+            SET status = 'rejected', verified_at = NOW(), verified_by = $1, rejection_note = $2, updated_at = NOW()
+            FROM achievement_team_members tm
+            WHERE tm.reference_id = ar.id AND tm.status = 'confirmed'
+              AND ar.mongo_achievement_id = $3 AND ar.id != $4 AND ar.status = 'submitted'
+            RETURNING ar.id
+        )
+        INSERT INTO achievement_status_history (reference_id, from_status, to_status, actor_id, note)
+        SELECT id, 'submitted', 'rejected', $1, $2
+        FROM updated
+    `, verifierID, note, mongoHex, excludeRefID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
 
-		if submittedAt.Valid {
-			item.SubmittedAt = &submittedAt.Time
-		}
-		if verifiedAt.Valid {
-			item.VerifiedAt = &verifiedAt.Time
-		}
-		if verifiedBy.Valid {
-			s := verifiedBy.String
-			item.VerifiedBy = &s
-		}
-		if rejectionNote.Valid {
-			s := rejectionNote.String
-			item.RejectionNote = &s
-		}
+// IsDocumentLocked mengembalikan true jika dokumen MongoDB sudah dipakai oleh
+// reference berstatus verified, sehingga isinya tidak boleh diubah lagi.
+func (r *AchievementRefRepo) IsDocumentLocked(mongoHex string) (bool, error) {
+	var locked bool
+	err := r.PG.QueryRow(`
+        SELECT EXISTS (
+            SELECT 1 FROM achievement_references
+            WHERE mongo_achievement_id = $1 AND status = 'verified'
+        )
+    `, mongoHex).Scan(&locked)
+	return locked, err
+}
 
+// ReopenReferences mengembalikan semua reference verified pada dokumen yang
+// sama ke status submitted agar direview ulang oleh dosen wali.
+func (r *AchievementRefRepo) ReopenReferences(tx *sql.Tx, mongoHex, actorID, reason string) ([]model.ReopenedReference, error) {
+	rows, err := tx.Query(`
+        WITH updated AS (
+            UPDATE achievement_references
+            SET status = 'submitted', submitted_at = NOW(), verified_at = NULL, verified_by = NULL,
+                review_started_at = NULL, review_started_by = NULL, sla_reminded_at = NULL, escalated_at = NULL,
+                updated_at = NOW()
+            WHERE mongo_achievement_id = $1 AND status = 'verified'
+            RETURNING id, student_id, version
+        ), history AS (
+            INSERT INTO achievement_status_history (reference_id, from_status, to_status, actor_id, note)
+            SELECT id, 'verified', 'submitted', $2, $3
+            FROM updated
+        )
+        SELECT u.id, u.student_id, s.advisor_id, u.version
+        FROM updated u
+        JOIN students s ON u.student_id = s.id
+    `, mongoHex, actorID, reason)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.ReopenedReference
+	for rows.Next() {
+		var item model.ReopenedReference
+		var advisorID sql.NullString
+		if err := rows.Scan(&item.ReferenceID, &item.StudentID, &advisorID, &item.Version); err != nil {
+			return nil, err
+		}
+		item.AdvisorID = advisorID.String
 		out = append(out, item)
 	}
+	return out, rows.Err()
+}
 
-	return out, nil
-}
\ No newline at end of file
+// FindActiveReferenceID mengembalikan id reference milik mahasiswa untuk
+// dokumen MongoDB tertentu, selama belum dihapus.
+func (r *AchievementRefRepo) FindActiveReferenceID(mongoHex, studentID string) (string, error) {
+	var id string
+	err := r.PG.QueryRow(`
+        SELECT id FROM achievement_references
+        WHERE mongo_achievement_id = $1 AND student_id = $2 AND status != 'deleted'
+    `, mongoHex, studentID).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-fiber/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AchievementRevisionRepo struct {
+	Coll *mongo.Collection
+}
+
+func NewAchievementRevisionRepo(db *mongo.Database) *AchievementRevisionRepo {
+	return &AchievementRevisionRepo{
+		Coll: db.Collection("achievement_revisions"),
+	}
+}
+
+// Create menyimpan snapshot baru dengan nomor revisi berikutnya. Jika nomor
+// bentrok karena penyimpanan bersamaan, nomor dihitung ulang.
+func (r *AchievementRevisionRepo) Create(ctx context.Context, rev model.AchievementRevision) (*model.AchievementRevision, error) {
+	rev.CreatedAt = time.Now()
+
+	for attempt := 0; attempt < 3; attempt++ {
+		var last model.AchievementRevision
+		opts := options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}}).SetProjection(bson.M{"revision": 1})
+		err := r.Coll.FindOne(ctx, bson.M{"achievementId": rev.AchievementID}, opts).Decode(&last)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		rev.Revision = last.Revision + 1
+
+		res, err := r.Coll.InsertOne(ctx, rev)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rev.ID = res.InsertedID.(primitive.ObjectID)
+		return &rev, nil
+	}
+
+	return nil, errors.New("gagal menentukan nomor revisi")
+}
+
+func (r *AchievementRevisionRepo) List(ctx context.Context, achievementID string) ([]model.AchievementRevision, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revision", Value: 1}}).
+		SetProjection(bson.M{"snapshot": 0})
+
+	cur, err := r.Coll.Find(ctx, bson.M{"achievementId": achievementID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []model.AchievementRevision{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *AchievementRevisionRepo) Get(ctx context.Context, achievementID string, revision int) (*model.AchievementRevision, error) {
+	var out model.AchievementRevision
+	err := r.Coll.FindOne(ctx, bson.M{"achievementId": achievementID, "revision": revision}).Decode(&out)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteByAchievement menghapus seluruh snapshot milik satu dokumen prestasi.
+func (r *AchievementRevisionRepo) DeleteByAchievement(ctx context.Context, achievementID string) (int64, error) {
+	res, err := r.Coll.DeleteMany(ctx, bson.M{"achievementId": achievementID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// ListAchievementIDs mengembalikan id dokumen prestasi yang memiliki snapshot.
+func (r *AchievementRevisionRepo) ListAchievementIDs(ctx context.Context) ([]string, error) {
+	values, err := r.Coll.Distinct(ctx, "achievementId", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
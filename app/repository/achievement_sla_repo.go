@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"go-fiber/app/model"
+)
+
+const slaCandidateSelect = `
+        SELECT ar.id, ar.student_id, u.full_name, COALESCE(s.study_program, ''),
+               s.advisor_id, ar.submitted_at
+        FROM achievement_references ar
+        JOIN students s ON ar.student_id = s.id
+        JOIN users u ON s.id = u.id
+`
+
+// adminScopePredicate membatasi data untuk admin $N: admin tanpa penugasan
+// menangani semua program studi, admin dengan penugasan hanya program
+// studinya, dan program studi tanpa admin tetap terlihat oleh semua admin
+// agar eskalasinya tidak tertinggal.
+const adminScopePredicate = `(
+            NOT EXISTS (SELECT 1 FROM admin_study_programs ap WHERE ap.admin_id = %[1]s)
+            OR EXISTS (SELECT 1 FROM admin_study_programs ap WHERE ap.admin_id = %[1]s AND ap.study_program = s.study_program)
+            OR NOT EXISTS (SELECT 1 FROM admin_study_programs ap WHERE ap.study_program = s.study_program)
+        )`
+
+func scanSLACandidates(rows *sql.Rows) ([]model.SLACandidate, error) {
+	defer rows.Close()
+
+	var out []model.SLACandidate
+	for rows.Next() {
+		var c model.SLACandidate
+		var advisorID sql.NullString
+		if err := rows.Scan(&c.ReferenceID, &c.StudentID, &c.StudentName, &c.StudyProgram, &advisorID, &c.SubmittedAt); err != nil {
+			return nil, err
+		}
+		c.AdvisorID = advisorID.String
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// ListSLAReminderDue mengembalikan submission yang sudah menunggu lebih dari
+// reminderHours jam dan belum pernah diingatkan ke dosen wali.
+func (r *AchievementRefRepo) ListSLAReminderDue(reminderHours, limit int) ([]model.SLACandidate, error) {
+	rows, err := r.PG.Query(slaCandidateSelect+`
+        WHERE ar.status = 'submitted' AND ar.sla_reminded_at IS NULL AND ar.escalated_at IS NULL
+          AND s.advisor_id IS NOT NULL
+          AND ar.submitted_at <= NOW() - make_interval(hours => $1)
+        ORDER BY ar.submitted_at
+        LIMIT $2
+    `, reminderHours, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSLACandidates(rows)
+}
+
+// ListSLAEscalationDue mengembalikan submission yang melewati batas SLA, atau
+// yang mahasiswanya tidak memiliki dosen wali sama sekali.
+func (r *AchievementRefRepo) ListSLAEscalationDue(dueHours, limit int) ([]model.SLACandidate, error) {
+	rows, err := r.PG.Query(slaCandidateSelect+`
+        WHERE ar.status = 'submitted' AND ar.escalated_at IS NULL
+          AND (s.advisor_id IS NULL OR ar.submitted_at <= NOW() - make_interval(hours => $1))
+        ORDER BY ar.submitted_at
+        LIMIT $2
+    `, dueHours, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSLACandidates(rows)
+}
+
+func (r *AchievementRefRepo) MarkSLAReminded(refID string) error {
+	_, err := r.PG.Exec(`
+        UPDATE achievement_references SET sla_reminded_at = NOW()
+        WHERE id = $1 AND status = 'submitted' AND sla_reminded_at IS NULL
+    `, refID)
+	return err
+}
+
+// MarkEscalated mengembalikan false jika reference sudah tidak submitted atau
+// sudah dieskalasi oleh proses lain.
+func (r *AchievementRefRepo) MarkEscalated(refID string) (bool, error) {
+	res, err := r.PG.Exec(`
+        UPDATE achievement_references SET escalated_at = NOW()
+        WHERE id = $1 AND status = 'submitted' AND escalated_at IS NULL
+    `, refID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ListEscalated mengembalikan antrean eskalasi untuk admin tertentu sesuai
+// program studi yang ditugaskan kepadanya, opsional difilter per program studi.
+func (r *AchievementRefRepo) ListEscalated(adminID, studyProgram string) ([]model.EscalatedAchievement, error) {
+	rows, err := r.PG.Query(`
+        SELECT ar.id, ar.mongo_achievement_id, ar.student_id, u.full_name,
+               COALESCE(s.study_program, ''), s.advisor_id, ar.submitted_at, ar.escalated_at
+        FROM achievement_references ar
+        JOIN students s ON ar.student_id = s.id
+        JOIN users u ON s.id = u.id
+        WHERE ar.status = 'submitted' AND ar.escalated_at IS NOT NULL
+          AND ($1 = '' OR s.study_program = $1)
+          AND `+fmt.Sprintf(adminScopePredicate, "$2")+`
+        ORDER BY COALESCE(s.study_program, ''), ar.submitted_at
+    `, studyProgram, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.EscalatedAchievement{}
+	for rows.Next() {
+		var item model.EscalatedAchievement
+		var advisorID sql.NullString
+		err := rows.Scan(
+			&item.ReferenceID, &item.MongoID, &item.StudentID, &item.StudentName,
+			&item.StudyProgram, &advisorID, &item.SubmittedAt, &item.EscalatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.AdvisorID = advisorID.String
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
+// AdminHandlesStudent mengembalikan true jika program studi mahasiswa termasuk
+// cakupan admin (lihat adminScopePredicate).
+func (r *AchievementRefRepo) AdminHandlesStudent(adminID, studentID string) (bool, error) {
+	var ok bool
+	err := r.PG.QueryRow(`
+        SELECT EXISTS (
+            SELECT 1 FROM students s
+            WHERE s.id = $2 AND `+fmt.Sprintf(adminScopePredicate, "$1")+`
+        )
+    `, adminID, studentID).Scan(&ok)
+	return ok, err
+}
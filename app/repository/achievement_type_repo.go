@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-fiber/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AchievementTypeRepo struct {
+	Coll *mongo.Collection
+}
+
+func NewAchievementTypeRepo(db *mongo.Database) *AchievementTypeRepo {
+	return &AchievementTypeRepo{
+		Coll: db.Collection("achievement_types"),
+	}
+}
+
+func decodeTypeDefinition(def *model.AchievementTypeDefinition) {
+	if def.SchemaJSON != "" {
+		def.DetailsSchema = json.RawMessage(def.SchemaJSON)
+	}
+}
+
+// FindLatest mengembalikan versi terbaru sebuah tipe, aktif maupun tidak.
+// Mengembalikan nil tanpa error jika tipe belum pernah didefinisikan.
+func (r *AchievementTypeRepo) FindLatest(ctx context.Context, code string) (*model.AchievementTypeDefinition, error) {
+	var out model.AchievementTypeDefinition
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err := r.Coll.FindOne(ctx, bson.M{"code": code}, opts).Decode(&out)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	decodeTypeDefinition(&out)
+	return &out, nil
+}
+
+func (r *AchievementTypeRepo) FindVersion(ctx context.Context, code string, version int) (*model.AchievementTypeDefinition, error) {
+	var out model.AchievementTypeDefinition
+	err := r.Coll.FindOne(ctx, bson.M{"code": code, "version": version}).Decode(&out)
+	if err != nil {
+		return nil, err
+	}
+	decodeTypeDefinition(&out)
+	return &out, nil
+}
+
+func (r *AchievementTypeRepo) ListActive(ctx context.Context) ([]model.AchievementTypeDefinition, error) {
+	cur, err := r.Coll.Find(ctx, bson.M{"isActive": true}, options.Find().SetSort(bson.D{{Key: "code", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []model.AchievementTypeDefinition{}
+	for cur.Next(ctx) {
+		var def model.AchievementTypeDefinition
+		if err := cur.Decode(&def); err != nil {
+			return nil, err
+		}
+		decodeTypeDefinition(&def)
+		out = append(out, def)
+	}
+	return out, cur.Err()
+}
+
+func (r *AchievementTypeRepo) ListVersions(ctx context.Context, code string) ([]model.AchievementTypeDefinition, error) {
+	cur, err := r.Coll.Find(ctx, bson.M{"code": code}, options.Find().SetSort(bson.D{{Key: "version", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []model.AchievementTypeDefinition{}
+	for cur.Next(ctx) {
+		var def model.AchievementTypeDefinition
+		if err := cur.Decode(&def); err != nil {
+			return nil, err
+		}
+		decodeTypeDefinition(&def)
+		out = append(out, def)
+	}
+	return out, cur.Err()
+}
+
+// Batas percobaan CreateVersion saat nomor versi sudah diambil penulis lain.
+const createVersionAttempts = 3
+
+// CreateVersion menyimpan definisi sebagai versi baru yang aktif lalu
+// menonaktifkan versi sebelumnya. Index unik (code, version) mencegah dua
+// penulis memakai nomor versi yang sama; yang kalah mengambil nomor berikutnya.
+// Selama jeda singkat di antara kedua langkah, FindLatest tetap mengembalikan
+// versi terbaru.
+func (r *AchievementTypeRepo) CreateVersion(ctx context.Context, def model.AchievementTypeDefinition) (*model.AchievementTypeDefinition, error) {
+	def.IsActive = true
+	def.CreatedAt = time.Now()
+
+	var err error
+	for attempt := 0; attempt < createVersionAttempts; attempt++ {
+		var latest *model.AchievementTypeDefinition
+		latest, err = r.FindLatest(ctx, def.Code)
+		if err != nil {
+			return nil, err
+		}
+		def.Version = 1
+		if latest != nil {
+			def.Version = latest.Version + 1
+		}
+
+		_, err = r.Coll.InsertOne(ctx, def)
+		if !mongo.IsDuplicateKeyError(err) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Coll.UpdateMany(ctx,
+		bson.M{"code": def.Code, "isActive": true, "version": bson.M{"$lt": def.Version}},
+		bson.M{"$set": bson.M{"isActive": false}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	decodeTypeDefinition(&def)
+	return &def, nil
+}
+
+func (r *AchievementTypeRepo) Deactivate(ctx context.Context, code string) (int64, error) {
+	res, err := r.Coll.UpdateMany(ctx, bson.M{"code": code, "isActive": true}, bson.M{"$set": bson.M{"isActive": false}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// directoryQuery membantu menyusun WHERE, ORDER BY, dan paginasi untuk
+// daftar pengguna, mahasiswa, dan dosen.
+type directoryQuery struct {
+	where []string
+	args  []interface{}
+}
+
+func (q *directoryQuery) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *directoryQuery) add(cond string) {
+	q.where = append(q.where, cond)
+}
+
+// search menambahkan pencarian ILIKE pada beberapa kolom sekaligus.
+func (q *directoryQuery) search(term string, columns ...string) {
+	if term == "" {
+		return
+	}
+	p := q.arg("%" + escapeLike(term) + "%")
+	conds := make([]string, len(columns))
+	for i, col := range columns {
+		conds[i] = col + " ILIKE " + p
+	}
+	q.add("(" + strings.Join(conds, " OR ") + ")")
+}
+
+func (q *directoryQuery) whereSQL() string {
+	if len(q.where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.where, " AND ")
+}
+
+// orderAndPage menghasilkan ORDER BY dari kolom yang sudah diwhitelist serta
+// LIMIT/OFFSET. tieBreaker menjaga urutan stabil antar halaman.
+func (q *directoryQuery) orderAndPage(columns map[string]string, sort, def, order, tieBreaker string, page, limit int) string {
+	column, ok := columns[sort]
+	if !ok {
+		column = columns[def]
+	}
+	dir := "ASC"
+	if order == "desc" {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s NULLS LAST, %s", column, dir, tieBreaker) +
+		" LIMIT " + q.arg(limit) + " OFFSET " + q.arg((page-1)*limit)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func sortFields(columns map[string]string) []string {
+	out := make([]string, 0, len(columns))
+	for k := range columns {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"go-fiber/app/model"
+)
+
+type IdempotencyRepo struct {
+	PG *sql.DB
+}
+
+func NewIdempotencyRepo(pg *sql.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{PG: pg}
+}
+
+// Reserve mencatat key sebagai sedang diproses. Key yang sudah melewati masa
+// retensi dipakai ulang. Mengembalikan false jika key masih dipegang request lain.
+func (r *IdempotencyRepo) Reserve(rec model.IdempotencyRecord, retentionHours int) (bool, error) {
+	var userID string
+	err := r.PG.QueryRow(`
+        INSERT INTO idempotency_keys (user_id, idem_key, method, path, request_hash)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id, idem_key) DO UPDATE
+        SET method = EXCLUDED.method, path = EXCLUDED.path, request_hash = EXCLUDED.request_hash,
+            status_code = NULL, content_type = NULL, response_headers = NULL, response_body = NULL, created_at = NOW()
+        WHERE idempotency_keys.created_at < NOW() - make_interval(hours => $6)
+        RETURNING user_id
+    `, rec.UserID, rec.Key, rec.Method, rec.Path, rec.RequestHash, retentionHours).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *IdempotencyRepo) Get(userID, key string) (*model.IdempotencyRecord, error) {
+	rec := model.IdempotencyRecord{UserID: userID, Key: key}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	var headers []byte
+
+	err := r.PG.QueryRow(`
+        SELECT method, path, request_hash, status_code, content_type, response_headers, response_body
+        FROM idempotency_keys
+        WHERE user_id = $1 AND idem_key = $2
+    `, userID, key).Scan(&rec.Method, &rec.Path, &rec.RequestHash, &statusCode, &contentType, &headers, &rec.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &rec.Headers); err != nil {
+			return nil, err
+		}
+	}
+
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		rec.StatusCode = &code
+	}
+	rec.ContentType = contentType.String
+	return &rec, nil
+}
+
+// Complete menyimpan response pertama agar bisa diputar ulang untuk retry.
+// headers berisi header response selain Content-Type yang ikut diputar ulang.
+func (r *IdempotencyRepo) Complete(userID, key string, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	var headersJSON []byte
+	if len(headers) > 0 {
+		var err error
+		if headersJSON, err = json.Marshal(headers); err != nil {
+			return err
+		}
+	}
+	_, err := r.PG.Exec(`
+        UPDATE idempotency_keys
+        SET status_code = $3, content_type = $4, response_headers = $5, response_body = $6
+        WHERE user_id = $1 AND idem_key = $2
+    `, userID, key, statusCode, contentType, headersJSON, body)
+	return err
+}
+
+// Release menghapus key yang request-nya gagal agar klien bisa mencoba lagi.
+func (r *IdempotencyRepo) Release(userID, key string) error {
+	_, err := r.PG.Exec(`DELETE FROM idempotency_keys WHERE user_id = $1 AND idem_key = $2`, userID, key)
+	return err
+}
+
+func (r *IdempotencyRepo) DeleteExpired(retentionHours int) (int64, error) {
+	res, err := r.PG.Exec(`
+        DELETE FROM idempotency_keys WHERE created_at < NOW() - make_interval(hours => $1)
+    `, retentionHours)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -5,27 +5,55 @@ import (
 	"go-fiber/app/model"
 )
 
-func GetAllLecturers(db *sql.DB) ([]model.LecturerListResponse, error) {
-	rows, err := db.Query(`
-		SELECT l.id, u.full_name, l.lecturer_id, l.department
+var lecturerSortColumns = map[string]string{
+	"created_at":  "l.created_at",
+	"full_name":   "u.full_name",
+	"lecturer_id": "l.lecturer_id",
+	"department":  "l.department",
+}
+
+func LecturerSortFields() []string { return sortFields(lecturerSortColumns) }
+
+// GetAllLecturers mengembalikan satu halaman dosen sesuai filter beserta total
+// baris yang cocok.
+func GetAllLecturers(db *sql.DB, f model.DirectoryFilter) ([]model.LecturerListResponse, int, error) {
+	var q directoryQuery
+	q.search(f.Search, "u.full_name", "l.lecturer_id", "u.email")
+	if f.Department != "" {
+		q.add("l.department = " + q.arg(f.Department))
+	}
+	if f.IsActive != nil {
+		q.add("COALESCE(u.is_active, true) = " + q.arg(*f.IsActive))
+	}
+
+	from := `
 		FROM lecturers l
-		JOIN users u ON l.id = u.id`)
+		JOIN users u ON l.id = u.id` + q.whereSQL()
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*)"+from, q.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.Query(`
+		SELECT l.id, u.full_name, l.lecturer_id, COALESCE(l.department, '')`+from+
+		q.orderAndPage(lecturerSortColumns, f.Sort, "full_name", f.Order, "l.id", f.Page, f.Limit), q.args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var list []model.LecturerListResponse
+	list := []model.LecturerListResponse{}
 
 	for rows.Next() {
 		var l model.LecturerListResponse
 		if err := rows.Scan(&l.ID, &l.FullName, &l.LecturerID, &l.Department); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		list = append(list, l)
 	}
 
-	return list, nil
+	return list, total, rows.Err()
 }
 
 func GetLecturerAdvisees(db *sql.DB, lecturerID string) ([]model.LecturerAdviseeResponse, error) {
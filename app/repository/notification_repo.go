@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"go-fiber/app/model"
+)
+
+type NotificationRepo struct {
+	PG *sql.DB
+}
+
+func NewNotificationRepo(pg *sql.DB) *NotificationRepo {
+	return &NotificationRepo{PG: pg}
+}
+
+func (r *NotificationRepo) Create(userID, notifType, title, message string, referenceID *string) error {
+	_, err := r.PG.Exec(`
+        INSERT INTO notifications (user_id, type, title, message, reference_id)
+        VALUES ($1, $2, $3, $4, $5)
+    `, userID, notifType, title, message, referenceID)
+	return err
+}
+
+func (r *NotificationRepo) ListForUser(userID string, unreadOnly bool) ([]model.Notification, error) {
+	rows, err := r.PG.Query(`
+        SELECT id, user_id, type, title, COALESCE(message, ''), reference_id, is_read, created_at
+        FROM notifications
+        WHERE user_id = $1 AND ($2 = false OR is_read = false)
+        ORDER BY created_at DESC
+        LIMIT 100
+    `, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		var referenceID sql.NullString
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &referenceID, &n.IsRead, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		if referenceID.Valid {
+			s := referenceID.String
+			n.ReferenceID = &s
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func (r *NotificationRepo) MarkRead(id, userID string) error {
+	res, err := r.PG.Exec(`
+        UPDATE notifications SET is_read = true WHERE id = $1 AND user_id = $2
+    `, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *NotificationRepo) MarkAllRead(userID string) error {
+	_, err := r.PG.Exec(`UPDATE notifications SET is_read = true WHERE user_id = $1 AND is_read = false`, userID)
+	return err
+}
+
+// CreateForStudyProgramAdmins mengirim notifikasi ke admin aktif yang
+// ditugaskan pada program studi dan mengembalikan jumlah penerima.
+func (r *NotificationRepo) CreateForStudyProgramAdmins(studyProgram, notifType, title, message string, referenceID *string) (int64, error) {
+	res, err := r.PG.Exec(`
+        INSERT INTO notifications (user_id, type, title, message, reference_id)
+        SELECT u.id, $2, $3, $4, $5
+        FROM admin_study_programs ap
+        JOIN users u ON ap.admin_id = u.id
+        JOIN roles r ON u.role_id = r.id
+        WHERE ap.study_program = $1 AND r.name = 'Admin' AND u.is_active = true
+    `, studyProgram, notifType, title, message, referenceID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// CreateForRole mengirim notifikasi yang sama ke semua user aktif dengan role tertentu.
+func (r *NotificationRepo) CreateForRole(roleName, notifType, title, message string, referenceID *string) error {
+	_, err := r.PG.Exec(`
+        INSERT INTO notifications (user_id, type, title, message, reference_id)
+        SELECT u.id, $2, $3, $4, $5
+        FROM users u
+        JOIN roles r ON u.role_id = r.id
+        WHERE r.name = $1 AND u.is_active = true
+    `, roleName, notifType, title, message, referenceID)
+	return err
+}
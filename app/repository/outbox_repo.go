@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"go-fiber/app/model"
+)
+
+type OutboxRepo struct {
+	PG *sql.DB
+}
+
+func NewOutboxRepo(pg *sql.DB) *OutboxRepo {
+	return &OutboxRepo{PG: pg}
+}
+
+// Enqueue mencatat operasi lintas store di dalam transaksi pemanggil. Event
+// baru tersedia untuk worker setelah delaySeconds, memberi kesempatan proses
+// inline menyelesaikannya lebih dulu.
+func (r *OutboxRepo) Enqueue(tx *sql.Tx, eventType, aggregateID string, payload interface{}, delaySeconds int) (string, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	err = tx.QueryRow(`
+        INSERT INTO outbox_events (event_type, aggregate_id, payload, available_at)
+        VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+        RETURNING id
+    `, eventType, aggregateID, raw, delaySeconds).Scan(&id)
+	return id, err
+}
+
+// Claim mengambil event pending yang sudah jatuh tempo dan menyewanya selama
+// leaseSeconds agar tidak diproses ganda oleh worker lain.
+func (r *OutboxRepo) Claim(limit, leaseSeconds int) ([]model.OutboxEvent, error) {
+	rows, err := r.PG.Query(`
+        UPDATE outbox_events
+        SET attempts = attempts + 1, available_at = NOW() + make_interval(secs => $2)
+        WHERE id IN (
+            SELECT id FROM outbox_events
+            WHERE status = 'pending' AND available_at <= NOW()
+            ORDER BY created_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, event_type, aggregate_id, payload, status, attempts, last_error, created_at
+    `, limit, leaseSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.OutboxEvent
+	for rows.Next() {
+		var e model.OutboxEvent
+		var lastError sql.NullString
+		if err := rows.Scan(&e.ID, &e.EventType, &e.AggregateID, &e.Payload, &e.Status, &e.Attempts, &lastError, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if lastError.Valid {
+			s := lastError.String
+			e.LastError = &s
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Complete menandai event selesai dengan status done, compensated, atau failed.
+func (r *OutboxRepo) Complete(id, status, lastError string) error {
+	_, err := r.PG.Exec(`
+        UPDATE outbox_events
+        SET status = $2, last_error = NULLIF($3, ''), processed_at = NOW()
+        WHERE id = $1 AND status = 'pending'
+    `, id, status, lastError)
+	return err
+}
+
+// Retry menjadwalkan ulang event yang gagal diproses.
+func (r *OutboxRepo) Retry(id, lastError string, delaySeconds int) error {
+	_, err := r.PG.Exec(`
+        UPDATE outbox_events
+        SET last_error = $2, available_at = NOW() + make_interval(secs => $3)
+        WHERE id = $1 AND status = 'pending'
+    `, id, lastError, delaySeconds)
+	return err
+}
+
+// DeleteProcessedBefore membersihkan event yang sudah selesai diproses.
+func (r *OutboxRepo) DeleteProcessedBefore(days int) (int64, error) {
+	res, err := r.PG.Exec(`
+        DELETE FROM outbox_events
+        WHERE status IN ('done', 'compensated') AND processed_at < NOW() - make_interval(days => $1)
+    `, days)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// PendingMongoIDs mengembalikan id dokumen MongoDB yang masih punya event
+// pending, agar rekonsiliasi tidak mengganggu proses yang sedang berjalan.
+func (r *OutboxRepo) PendingMongoIDs() (map[string]bool, error) {
+	rows, err := r.PG.Query(`
+        SELECT DISTINCT payload->>'mongo_id' FROM outbox_events
+        WHERE status = 'pending' AND payload ? 'mongo_id'
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// HasPendingCreate mengembalikan true jika dokumen MongoDB masih menunggu event
+// pembuatan dari outbox.
+func (r *OutboxRepo) HasPendingCreate(mongoHex string) (bool, error) {
+	var pending bool
+	err := r.PG.QueryRow(`
+        SELECT EXISTS (
+            SELECT 1 FROM outbox_events
+            WHERE status = 'pending' AND event_type = $1 AND payload->>'mongo_id' = $2
+        )
+    `, model.OutboxAchievementCreate, mongoHex).Scan(&pending)
+	return pending, err
+}
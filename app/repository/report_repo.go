@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go-fiber/app/model"
+
+	"github.com/lib/pq"
+)
+
+type ReportRepo struct {
+	PG *sql.DB
+}
+
+func NewReportRepo(pg *sql.DB) *ReportRepo {
+	return &ReportRepo{PG: pg}
+}
+
+// ListReportKeys mengembalikan reference beserta data mahasiswa yang cocok
+// dengan filter laporan. Reference berstatus deleted tidak pernah ikut.
+func (r *ReportRepo) ListReportKeys(f model.ReportFilter) ([]model.ReportKey, error) {
+	where := []string{"ar.status != 'deleted'"}
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(f.Statuses) > 0 {
+		where = append(where, "ar.status = ANY("+arg(pq.Array(f.Statuses))+")")
+	}
+	if f.StudyProgram != "" {
+		where = append(where, "s.study_program = "+arg(f.StudyProgram))
+	}
+	if f.YearOfEntry != 0 {
+		where = append(where, "s.year_of_entry = "+arg(f.YearOfEntry))
+	}
+	if f.AdvisorID != "" {
+		where = append(where, "s.advisor_id = "+arg(f.AdvisorID))
+	}
+
+	rows, err := r.PG.Query(`
+        SELECT ar.id, ar.student_id, u.full_name, s.student_id, ar.mongo_achievement_id, ar.status,
+               COALESCE(s.study_program, ''), COALESCE(s.year_of_entry, 0), s.advisor_id,
+               s.leaderboard_opt_out, ar.submitted_at, ar.verified_at
+        FROM achievement_references ar
+        JOIN students s ON ar.student_id = s.id
+        JOIN users u ON s.id = u.id
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY ar.created_at
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.ReportKey
+	for rows.Next() {
+		var k model.ReportKey
+		var advisorID sql.NullString
+		var submittedAt, verifiedAt sql.NullTime
+		if err := rows.Scan(&k.ReferenceID, &k.StudentID, &k.StudentName, &k.StudentNIM, &k.MongoID, &k.Status,
+			&k.StudyProgram, &k.YearOfEntry, &advisorID, &k.OptOut, &submittedAt, &verifiedAt); err != nil {
+			return nil, err
+		}
+		k.AdvisorID = advisorID.String
+		if submittedAt.Valid {
+			k.SubmittedAt = &submittedAt.Time
+		}
+		if verifiedAt.Valid {
+			k.VerifiedAt = &verifiedAt.Time
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+// SetLeaderboardOptOut menyimpan pilihan mahasiswa untuk tidak ditampilkan
+// di leaderboard.
+func (r *ReportRepo) SetLeaderboardOptOut(studentID string, optOut bool) error {
+	res, err := r.PG.Exec(`UPDATE students SET leaderboard_opt_out = $2 WHERE id = $1`, studentID, optOut)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListAdvisorWorkloads menghitung beban tiap dosen. Pending dan overdue adalah
+// kondisi saat ini dari reference mahasiswa bimbingannya. Keputusan dan
+// turnaround diambil dari riwayat status: dihitung untuk dosen yang memutuskan
+// (actor_id), dibatasi periode waktu keputusan, dan turnaround diukur dari
+// masuknya reference ke status submitted terakhir sebelum keputusan itu.
+func (r *ReportRepo) ListAdvisorWorkloads(f model.AdvisorWorkloadFilter) ([]model.AdvisorWorkload, error) {
+	var where []string
+	args := []interface{}{f.DueHours, f.From, f.To}
+	if f.LecturerID != "" {
+		args = append(args, f.LecturerID)
+		where = append(where, fmt.Sprintf("l.id = $%d", len(args)))
+	}
+	if f.Department != "" {
+		args = append(args, f.Department)
+		where = append(where, fmt.Sprintf("l.department = $%d", len(args)))
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	rows, err := r.PG.Query(`
+        WITH pending AS (
+            SELECT s.advisor_id,
+                   COUNT(*) AS pending_count,
+                   COUNT(*) FILTER (WHERE ar.submitted_at <= NOW() - make_interval(hours => $1)) AS overdue_count,
+                   MIN(ar.submitted_at) AS oldest_pending_at
+            FROM achievement_references ar
+            JOIN students s ON ar.student_id = s.id
+            WHERE s.advisor_id IS NOT NULL AND ar.status = 'submitted'
+            GROUP BY s.advisor_id
+        ), decisions AS (
+            SELECT h.actor_id, h.to_status,
+                   EXTRACT(EPOCH FROM (h.created_at - sub.created_at)) / 3600 AS turnaround
+            FROM achievement_status_history h
+            LEFT JOIN LATERAL (
+                SELECT p.created_at
+                FROM achievement_status_history p
+                WHERE p.reference_id = h.reference_id AND p.to_status = 'submitted'
+                  AND p.created_at <= h.created_at
+                ORDER BY p.created_at DESC
+                LIMIT 1
+            ) sub ON true
+            WHERE h.from_status = 'submitted' AND h.to_status IN ('verified', 'rejected')
+              AND h.actor_id IS NOT NULL
+              AND ($2::timestamp IS NULL OR h.created_at >= $2)
+              AND ($3::timestamp IS NULL OR h.created_at < $3)
+        ), decided AS (
+            SELECT actor_id,
+                   COUNT(*) AS decided_count,
+                   COUNT(*) FILTER (WHERE to_status = 'rejected') AS rejected_count,
+                   AVG(turnaround) AS avg_turnaround,
+                   percentile_cont(0.9) WITHIN GROUP (ORDER BY turnaround) AS p90_turnaround
+            FROM decisions
+            GROUP BY actor_id
+        )
+        SELECT l.id, u.full_name, l.lecturer_id, COALESCE(l.department, ''),
+               (SELECT COUNT(*) FROM students s WHERE s.advisor_id = l.id),
+               COALESCE(p.pending_count, 0),
+               COALESCE(p.overdue_count, 0),
+               p.oldest_pending_at,
+               COALESCE(d.decided_count, 0),
+               COALESCE(d.rejected_count, 0),
+               d.avg_turnaround,
+               d.p90_turnaround
+        FROM lecturers l
+        JOIN users u ON l.id = u.id
+        LEFT JOIN pending p ON p.advisor_id = l.id
+        LEFT JOIN decided d ON d.actor_id = l.id`+whereSQL+`
+        ORDER BY 7 DESC, 6 DESC, u.full_name
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.AdvisorWorkload{}
+	for rows.Next() {
+		var w model.AdvisorWorkload
+		var oldest sql.NullTime
+		var avg, p90 sql.NullFloat64
+		if err := rows.Scan(&w.ID, &w.FullName, &w.LecturerID, &w.Department, &w.AdviseeCount,
+			&w.PendingCount, &w.OverdueCount, &oldest, &w.DecidedCount, &w.RejectedCount, &avg, &p90); err != nil {
+			return nil, err
+		}
+		if oldest.Valid {
+			w.OldestPendingAt = &oldest.Time
+		}
+		if avg.Valid {
+			w.AvgTurnaroundHours = &avg.Float64
+		}
+		if p90.Valid {
+			w.P90TurnaroundHours = &p90.Float64
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// ListOverdueForAdvisor mengembalikan submission mahasiswa bimbingan yang
+// melewati batas SLA, dari yang paling lama menunggu.
+func (r *ReportRepo) ListOverdueForAdvisor(lecturerID string, dueHours int) ([]model.OverdueItem, error) {
+	rows, err := r.PG.Query(`
+        SELECT ar.id, s.id, u.full_name, s.student_id, ar.submitted_at, ar.escalated_at
+        FROM achievement_references ar
+        JOIN students s ON ar.student_id = s.id
+        JOIN users u ON s.id = u.id
+        WHERE s.advisor_id = $1 AND ar.status = 'submitted'
+          AND ar.submitted_at <= NOW() - make_interval(hours => $2)
+        ORDER BY ar.submitted_at
+    `, lecturerID, dueHours)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.OverdueItem{}
+	for rows.Next() {
+		var it model.OverdueItem
+		var escalatedAt sql.NullTime
+		if err := rows.Scan(&it.ReferenceID, &it.StudentID, &it.StudentName, &it.StudentNIM, &it.SubmittedAt, &escalatedAt); err != nil {
+			return nil, err
+		}
+		if escalatedAt.Valid {
+			it.EscalatedAt = &escalatedAt.Time
+		}
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"go-fiber/app/model"
+
+	"github.com/lib/pq"
+)
+
+type SKPIRepo struct {
+	PG *sql.DB
+}
+
+func NewSKPIRepo(pg *sql.DB) *SKPIRepo {
+	return &SKPIRepo{PG: pg}
+}
+
+// ListVerifiedEntries mengembalikan reference verified milik mahasiswa
+// beserta nama verifikatornya, diurutkan dari verifikasi paling awal.
+func (r *SKPIRepo) ListVerifiedEntries(studentID string) ([]model.SKPIEntry, error) {
+	rows, err := r.PG.Query(`
+        SELECT ar.id, ar.mongo_achievement_id, ar.verified_at, COALESCE(v.full_name, '')
+        FROM achievement_references ar
+        LEFT JOIN users v ON ar.verified_by = v.id
+        WHERE ar.student_id = $1 AND ar.status = 'verified' AND ar.verified_at IS NOT NULL
+        ORDER BY ar.verified_at, ar.id
+    `, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.SKPIEntry
+	for rows.Next() {
+		var e model.SKPIEntry
+		if err := rows.Scan(&e.ReferenceID, &e.MongoID, &e.VerifiedAt, &e.VerifierName); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// NextDocumentNumber mengambil nomor urut berikutnya untuk prefix dan tahun
+// tertentu, misalnya SKPI/2026/00001. Nomor dikunci di dalam transaksi
+// sehingga tidak ada dua dokumen dengan nomor yang sama.
+func (r *SKPIRepo) NextDocumentNumber(tx *sql.Tx, prefix string, year int) (string, error) {
+	scope := fmt.Sprintf("%s-%d", prefix, year)
+
+	var seq int
+	err := tx.QueryRow(`
+        INSERT INTO document_sequences (scope, last_value) VALUES ($1, 1)
+        ON CONFLICT (scope) DO UPDATE SET last_value = document_sequences.last_value + 1
+        RETURNING last_value
+    `, scope).Scan(&seq)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%d/%05d", prefix, year, seq), nil
+}
+
+func (r *SKPIRepo) InsertDocument(tx *sql.Tx, doc *model.SKPIDocument) error {
+	return tx.QueryRow(`
+        INSERT INTO skpi_documents (document_number, student_id, generated_by, reference_ids, achievement_count, total_points)
+        VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6)
+        RETURNING id, created_at
+    `, doc.DocumentNumber, doc.StudentID, doc.GeneratedBy, pq.Array(doc.ReferenceIDs),
+		doc.AchievementCount, doc.TotalPoints).Scan(&doc.ID, &doc.CreatedAt)
+}
@@ -6,24 +6,58 @@ import (
 )
 
 
-func GetAllStudents(db *sql.DB) ([]model.StudentListResponse, error) {
-	rows, err := db.Query(`
-		SELECT s.id, u.full_name, s.student_id, s.study_program, s.year_of_entry,
-		       a.full_name AS advisor_name
+var studentSortColumns = map[string]string{
+	"created_at":    "s.created_at",
+	"full_name":     "u.full_name",
+	"student_id":    "s.student_id",
+	"study_program": "s.study_program",
+	"year_of_entry": "s.year_of_entry",
+}
+
+func StudentSortFields() []string { return sortFields(studentSortColumns) }
+
+// GetAllStudents mengembalikan satu halaman mahasiswa sesuai filter beserta
+// total baris yang cocok.
+func GetAllStudents(db *sql.DB, f model.DirectoryFilter) ([]model.StudentListResponse, int, error) {
+	var q directoryQuery
+	q.search(f.Search, "u.full_name", "s.student_id", "u.email")
+	if f.StudyProgram != "" {
+		q.add("s.study_program = " + q.arg(f.StudyProgram))
+	}
+	if f.YearOfEntry != 0 {
+		q.add("s.year_of_entry = " + q.arg(f.YearOfEntry))
+	}
+	if f.AdvisorID != "" {
+		q.add("s.advisor_id = " + q.arg(f.AdvisorID))
+	}
+	if f.IsActive != nil {
+		q.add("COALESCE(u.is_active, true) = " + q.arg(*f.IsActive))
+	}
+
+	from := `
 		FROM students s
 		JOIN users u ON s.id = u.id
-		LEFT JOIN users a ON s.advisor_id = a.id
-		ORDER BY s.created_at DESC`)
+		LEFT JOIN users a ON s.advisor_id = a.id` + q.whereSQL()
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*)"+from, q.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.Query(`
+		SELECT s.id, u.full_name, s.student_id, s.study_program, s.year_of_entry,
+		       s.advisor_id, a.full_name AS advisor_name`+from+
+		q.orderAndPage(studentSortColumns, f.Sort, "created_at", f.Order, "s.id", f.Page, f.Limit), q.args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var students []model.StudentListResponse
+	students := []model.StudentListResponse{}
 
 	for rows.Next() {
 		var s model.StudentListResponse
-		var advisor *string
+		var advisorID, advisor *string
 
 		if err := rows.Scan(
 			&s.ID,
@@ -31,17 +65,19 @@ func GetAllStudents(db *sql.DB) ([]model.StudentListResponse, error) {
 			&s.StudentID,
 			&s.StudyProgram,
 			&s.YearOfEntry,
+			&advisorID,
 			&advisor,
 		); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
+		s.AdvisorID = advisorID
 		s.AdvisorName = advisor
 
 		students = append(students, s)
 	}
 
-	return students, nil
+	return students, total, rows.Err()
 }
 
 func GetStudentByID(db *sql.DB, id string) (*model.StudentDetailResponse, error) {
@@ -49,13 +85,13 @@ func GetStudentByID(db *sql.DB, id string) (*model.StudentDetailResponse, error)
 
 	err := db.QueryRow(`
 		SELECT s.id, u.full_name, s.student_id, s.study_program, s.year_of_entry,
-		       a.full_name AS advisor_name
+		       s.advisor_id, a.full_name AS advisor_name
 		FROM students s
 		JOIN users u ON s.id = u.id
 		LEFT JOIN users a ON s.advisor_id = a.id
 		WHERE s.id = $1
 	`, id).Scan(
-		&s.ID, &s.FullName, &s.StudentID, &s.StudyProgram, &s.YearOfEntry, &s.AdvisorName,
+		&s.ID, &s.FullName, &s.StudentID, &s.StudyProgram, &s.YearOfEntry, &s.AdvisorID, &s.AdvisorName,
 	)
 
 	if err != nil {
@@ -148,4 +184,10 @@ func UpdateStudentAdvisor(db *sql.DB, studentID, advisorID string) error {
 		UPDATE students SET advisor_id = $1 WHERE id = $2`,
 		advisorID, studentID)
 	return err
-}
\ No newline at end of file
+}
+// CountStudentsInProgram mengembalikan jumlah mahasiswa pada program studi.
+func CountStudentsInProgram(db *sql.DB, studyProgram string) (int, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM students WHERE study_program = $1`, studyProgram).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"database/sql"
+	"go-fiber/app/model"
+)
+
+type TeamMemberRepo struct {
+	PG *sql.DB
+}
+
+func NewTeamMemberRepo(pg *sql.DB) *TeamMemberRepo {
+	return &TeamMemberRepo{PG: pg}
+}
+
+const teamMemberSelect = `
+        SELECT tm.id, tm.mongo_achievement_id, tm.student_id, s.student_id, u.full_name,
+               tm.invited_by, tm.status, tm.reference_id, ar.status,
+               tm.invited_at, tm.responded_at
+        FROM achievement_team_members tm
+        JOIN students s ON tm.student_id = s.id
+        JOIN users u ON s.id = u.id
+        LEFT JOIN achievement_references ar ON tm.reference_id = ar.id
+`
+
+func scanTeamMember(scan func(dest ...interface{}) error) (*model.TeamMember, error) {
+	var m model.TeamMember
+	var invitedBy, referenceID, referenceStatus sql.NullString
+	var respondedAt sql.NullTime
+
+	err := scan(
+		&m.ID, &m.MongoID, &m.StudentID, &m.StudentNIM, &m.FullName,
+		&invitedBy, &m.Status, &referenceID, &referenceStatus,
+		&m.InvitedAt, &respondedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitedBy.Valid {
+		s := invitedBy.String
+		m.InvitedBy = &s
+	}
+	if referenceID.Valid {
+		s := referenceID.String
+		m.ReferenceID = &s
+	}
+	if referenceStatus.Valid {
+		s := referenceStatus.String
+		m.ReferenceStatus = &s
+	}
+	if respondedAt.Valid {
+		m.RespondedAt = &respondedAt.Time
+	}
+
+	return &m, nil
+}
+
+// Invite membuat undangan baru, atau mengaktifkan kembali undangan yang
+// sebelumnya ditolak. Anggota yang sudah konfirmasi tidak diubah. Dijalankan
+// di dalam transaksi pemanggil agar undangan tersimpan bersama prestasinya.
+func (r *TeamMemberRepo) Invite(tx *sql.Tx, mongoHex, studentID, invitedBy string) error {
+	_, err := tx.Exec(`
+        INSERT INTO achievement_team_members (mongo_achievement_id, student_id, invited_by, status, invited_at)
+        VALUES ($1, $2, $3, 'invited', NOW())
+        ON CONFLICT (mongo_achievement_id, student_id)
+        DO UPDATE SET status = 'invited', invited_by = $3, invited_at = NOW(), responded_at = NULL
+        WHERE achievement_team_members.status = 'declined'
+    `, mongoHex, studentID, invitedBy)
+	return err
+}
+
+func (r *TeamMemberRepo) GetByID(id string) (*model.TeamMember, error) {
+	return scanTeamMember(r.PG.QueryRow(teamMemberSelect+` WHERE tm.id = $1`, id).Scan)
+}
+
+func (r *TeamMemberRepo) ListByAchievement(mongoHex string) ([]model.TeamMember, error) {
+	rows, err := r.PG.Query(teamMemberSelect+` WHERE tm.mongo_achievement_id = $1 ORDER BY tm.invited_at`, mongoHex)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.TeamMember{}
+	for rows.Next() {
+		m, err := scanTeamMember(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *m)
+	}
+	return out, nil
+}
+
+func (r *TeamMemberRepo) ListPendingForStudent(studentID string) ([]model.TeamMember, error) {
+	rows, err := r.PG.Query(teamMemberSelect+` WHERE tm.student_id = $1 AND tm.status = 'invited' ORDER BY tm.invited_at DESC`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.TeamMember{}
+	for rows.Next() {
+		m, err := scanTeamMember(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *m)
+	}
+	return out, nil
+}
+
+// Confirm membuat achievement_references milik anggota dan menandai undangan
+// sebagai confirmed dalam satu transaksi.
+func (r *TeamMemberRepo) Confirm(inviteID, studentID, mongoHex string) (string, error) {
+	tx, err := r.PG.Begin()
+	if err != nil {
+		return "", err
+	}
+
+	var refID string
+	err = tx.QueryRow(`
+        INSERT INTO achievement_references
+        (student_id, mongo_achievement_id, status, created_at, updated_at)
+        VALUES ($1, $2, 'draft', NOW(), NOW())
+        RETURNING id
+    `, studentID, mongoHex).Scan(&refID)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	if err := insertStatusHistory(tx, refID, "", "draft", studentID, "Bergabung sebagai anggota tim"); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	res, err := tx.Exec(`
+        UPDATE achievement_team_members
+        SET status = 'confirmed', reference_id = $1, responded_at = NOW()
+        WHERE id = $2 AND status = 'invited'
+    `, refID, inviteID)
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return "", sql.ErrNoRows
+	}
+
+	return refID, tx.Commit()
+}
+
+func (r *TeamMemberRepo) Decline(inviteID string) error {
+	res, err := r.PG.Exec(`
+        UPDATE achievement_team_members
+        SET status = 'declined', responded_at = NOW()
+        WHERE id = $1 AND status = 'invited'
+    `, inviteID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -5,28 +5,55 @@ import (
 	"go-fiber/app/model"
 )
 
-func GetAllUsers(db *sql.DB) ([]model.UserListResponse, error) {
-	rows, err := db.Query(`
-		SELECT u.id, u.username, u.full_name, r.name AS role
+var userSortColumns = map[string]string{
+	"created_at": "u.created_at",
+	"full_name":  "u.full_name",
+	"username":   "u.username",
+	"email":      "u.email",
+}
+
+func UserSortFields() []string { return sortFields(userSortColumns) }
+
+// GetAllUsers mengembalikan satu halaman pengguna sesuai filter beserta total
+// baris yang cocok.
+func GetAllUsers(db *sql.DB, f model.DirectoryFilter) ([]model.UserListResponse, int, error) {
+	var q directoryQuery
+	q.search(f.Search, "u.username", "u.full_name", "u.email")
+	if f.Role != "" {
+		q.add("r.name = " + q.arg(f.Role))
+	}
+	if f.IsActive != nil {
+		q.add("COALESCE(u.is_active, true) = " + q.arg(*f.IsActive))
+	}
+
+	from := `
 		FROM users u
-		JOIN roles r ON u.role_id = r.id
-		ORDER BY u.created_at DESC`)
+		JOIN roles r ON u.role_id = r.id` + q.whereSQL()
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*)"+from, q.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.Query(`
+		SELECT u.id, u.username, u.full_name, u.email, r.name AS role, COALESCE(u.is_active, true)`+from+
+		q.orderAndPage(userSortColumns, f.Sort, "created_at", f.Order, "u.id", f.Page, f.Limit), q.args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var users []model.UserListResponse
+	users := []model.UserListResponse{}
 
 	for rows.Next() {
 		var u model.UserListResponse
-		if err := rows.Scan(&u.ID, &u.Username, &u.FullName, &u.Role); err != nil {
-			return nil, err
+		if err := rows.Scan(&u.ID, &u.Username, &u.FullName, &u.Email, &u.Role, &u.IsActive); err != nil {
+			return nil, 0, err
 		}
 		users = append(users, u)
 	}
 
-	return users, nil
+	return users, total, rows.Err()
 }
 
 func GetUserDetail(db *sql.DB, id string) (*model.UserDetailResponse, error) {
@@ -139,4 +166,62 @@ func UpdateUserRole(db *sql.DB, id string, roleName string) error {
 func DeleteUser(db *sql.DB, id string) error {
 	_, err := db.Exec(`DELETE FROM users WHERE id = $1`, id)
 	return err
-}
\ No newline at end of file
+}
+// GetAdminStudyPrograms mengembalikan program studi yang ditugaskan ke admin.
+func GetAdminStudyPrograms(db *sql.DB, adminID string) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT study_program FROM admin_study_programs
+		WHERE admin_id = $1
+		ORDER BY study_program`, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []string{}
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// SetAdminStudyPrograms mengganti penugasan program studi admin dalam satu
+// transaksi. Mengembalikan sql.ErrNoRows jika user bukan admin.
+func SetAdminStudyPrograms(db *sql.DB, adminID string, programs []string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var isAdmin bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM users u JOIN roles r ON u.role_id = r.id
+			WHERE u.id = $1 AND r.name = 'Admin'
+		)`, adminID).Scan(&isAdmin)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(`DELETE FROM admin_study_programs WHERE admin_id = $1`, adminID); err != nil {
+		return err
+	}
+	for _, p := range programs {
+		_, err := tx.Exec(`
+			INSERT INTO admin_study_programs (admin_id, study_program)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`, adminID, p)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-fiber/app/model"
+)
+
+var (
+	achievementTypes  = []string{model.AchievementTypeCompetition, model.AchievementTypePublication, model.AchievementTypeOrganization, model.AchievementTypeCertification, model.AchievementTypeOther}
+	competitionLevels = []string{"international", "national", "regional", "local"}
+	medalTypes        = []string{"gold", "silver", "bronze", "honorable_mention"}
+	publicationTypes  = []string{"journal", "conference", "book"}
+
+	issnPattern = regexp.MustCompile(`^\d{4}-\d{3}[\dXx]$`)
+)
+
+// Field milik tiap tipe prestasi. Field yang dikenal tetapi milik tipe lain
+// ditolak, sedangkan field yang tidak dikenal masuk ke customFields.
+var typeDetailFields = map[string][]string{
+	model.AchievementTypeCompetition:   {"competitionName", "competitionLevel", "rank", "medalType"},
+	model.AchievementTypePublication:   {"publicationType", "publicationTitle", "authors", "publisher", "issn"},
+	model.AchievementTypeOrganization:  {"organizationName", "position", "period"},
+	model.AchievementTypeCertification: {"certificationName", "issuedBy", "certificationNumber", "validUntil"},
+	model.AchievementTypeOther:         {},
+}
+
+var commonDetailFields = []string{"eventDate", "location", "organizer", "score", "customFields"}
+
+func isKnownAchievementType(achType string) bool {
+	return contains(achievementTypes, achType)
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+type detailsParser struct {
+	raw  map[string]interface{}
+	errs []model.FieldError
+}
+
+func (p *detailsParser) fail(key, msg string) {
+	p.errs = append(p.errs, model.FieldError{Field: "details." + key, Message: msg})
+}
+
+func (p *detailsParser) str(key string, required bool) string {
+	v, ok := p.raw[key]
+	if !ok || v == nil {
+		if required {
+			p.fail(key, "wajib diisi")
+		}
+		return ""
+	}
+	s, ok := v.(string)
+	if !ok {
+		p.fail(key, "harus berupa teks")
+		return ""
+	}
+	s = strings.TrimSpace(s)
+	if s == "" && required {
+		p.fail(key, "wajib diisi")
+	}
+	return s
+}
+
+func (p *detailsParser) oneOf(key string, required bool, allowed []string) string {
+	s := p.str(key, required)
+	if s != "" && !contains(allowed, s) {
+		p.fail(key, "harus salah satu dari: "+strings.Join(allowed, ", "))
+		return ""
+	}
+	return s
+}
+
+func (p *detailsParser) integer(key string, min int) int {
+	v, ok := p.raw[key]
+	if !ok || v == nil {
+		return 0
+	}
+	f, ok := v.(float64)
+	if !ok || f != float64(int(f)) {
+		p.fail(key, "harus berupa bilangan bulat")
+		return 0
+	}
+	if int(f) < min {
+		p.fail(key, fmt.Sprintf("minimal %d", min))
+		return 0
+	}
+	return int(f)
+}
+
+func (p *detailsParser) number(key string) float64 {
+	v, ok := p.raw[key]
+	if !ok || v == nil {
+		return 0
+	}
+	f, ok := v.(float64)
+	if !ok {
+		p.fail(key, "harus berupa angka")
+		return 0
+	}
+	if f < 0 {
+		p.fail(key, "tidak boleh negatif")
+		return 0
+	}
+	return f
+}
+
+func (p *detailsParser) strList(key string, required bool) []string {
+	v, ok := p.raw[key]
+	if !ok || v == nil {
+		if required {
+			p.fail(key, "wajib diisi")
+		}
+		return nil
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		p.fail(key, "harus berupa daftar teks")
+		return nil
+	}
+	out := make([]string, 0, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			p.fail(fmt.Sprintf("%s[%d]", key, i), "harus berupa teks yang tidak kosong")
+			continue
+		}
+		out = append(out, strings.TrimSpace(s))
+	}
+	if required && len(out) == 0 {
+		p.fail(key, "minimal berisi satu item")
+	}
+	return out
+}
+
+func parseDetailDate(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func (p *detailsParser) date(key string, required bool) *time.Time {
+	s := p.str(key, required)
+	if s == "" {
+		return nil
+	}
+	t, ok := parseDetailDate(s)
+	if !ok {
+		p.fail(key, "format tanggal tidak valid (YYYY-MM-DD atau RFC3339)")
+		return nil
+	}
+	return &t
+}
+
+func (p *detailsParser) period(key string, required bool) *model.Period {
+	v, ok := p.raw[key]
+	if !ok || v == nil {
+		if required {
+			p.fail(key, "wajib diisi")
+		}
+		return nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		p.fail(key, "harus berupa objek {start, end}")
+		return nil
+	}
+	sub := &detailsParser{raw: m}
+	start := sub.date("start", true)
+	end := sub.date("end", false)
+	for _, e := range sub.errs {
+		p.fail(key+"."+strings.TrimPrefix(e.Field, "details."), e.Message)
+	}
+	if start == nil {
+		return nil
+	}
+	out := &model.Period{Start: *start}
+	if end != nil {
+		if end.Before(*start) {
+			p.fail(key+".end", "tidak boleh sebelum tanggal mulai")
+			return nil
+		}
+		out.End = *end
+	}
+	return out
+}
+
+// parseAchievementDetails memetakan details mentah dari request ke
+// AchievementDetails sesuai achievementType dan mengembalikan error per field.
+func parseAchievementDetails(achType string, raw map[string]interface{}) (model.AchievementDetails, []model.FieldError) {
+	var d model.AchievementDetails
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	p := &detailsParser{raw: raw}
+
+	switch achType {
+	case model.AchievementTypeCompetition:
+		d.CompetitionName = p.str("competitionName", true)
+		d.CompetitionLevel = p.oneOf("competitionLevel", true, competitionLevels)
+		d.Rank = p.integer("rank", 1)
+		d.MedalType = p.oneOf("medalType", false, medalTypes)
+	case model.AchievementTypePublication:
+		d.PublicationType = p.oneOf("publicationType", true, publicationTypes)
+		d.PublicationTitle = p.str("publicationTitle", true)
+		d.Authors = p.strList("authors", true)
+		d.Publisher = p.str("publisher", false)
+		d.ISSN = p.str("issn", false)
+		if d.ISSN != "" && !issnPattern.MatchString(d.ISSN) {
+			p.fail("issn", "format ISSN tidak valid (contoh: 1234-567X)")
+		}
+	case model.AchievementTypeOrganization:
+		d.OrganizationName = p.str("organizationName", true)
+		d.Position = p.str("position", true)
+		d.Period = p.period("period", true)
+	case model.AchievementTypeCertification:
+		d.CertificationName = p.str("certificationName", true)
+		d.IssuedBy = p.str("issuedBy", true)
+		d.CertificationNumber = p.str("certificationNumber", false)
+		d.ValidUntil = p.date("validUntil", false)
+	case model.AchievementTypeOther:
+	default:
+		return d, []model.FieldError{{Field: "achievement_type", Message: "harus salah satu dari: " + strings.Join(achievementTypes, ", ")}}
+	}
+
+	d.EventDate = p.date("eventDate", false)
+	d.Location = p.str("location", false)
+	d.Organizer = p.str("organizer", false)
+	d.Score = p.number("score")
+
+	if d.EventDate != nil && d.ValidUntil != nil && d.ValidUntil.Before(*d.EventDate) {
+		p.fail("validUntil", "tidak boleh sebelum eventDate")
+	}
+
+	custom := map[string]interface{}{}
+	if v, ok := raw["customFields"]; ok && v != nil {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			p.fail("customFields", "harus berupa objek")
+		}
+		for k, val := range m {
+			custom[k] = val
+		}
+	}
+
+	own := typeDetailFields[achType]
+	for key, val := range raw {
+		if contains(own, key) || contains(commonDetailFields, key) {
+			continue
+		}
+		if owner := detailFieldOwner(key); owner != "" {
+			p.fail(key, fmt.Sprintf("hanya berlaku untuk tipe %s", owner))
+			continue
+		}
+		custom[key] = val
+	}
+	if len(custom) > 0 {
+		d.CustomFields = custom
+	}
+
+	return d, p.errs
+}
+
+func detailFieldOwner(key string) string {
+	for achType, fields := range typeDetailFields {
+		if contains(fields, key) {
+			return achType
+		}
+	}
+	return ""
+}
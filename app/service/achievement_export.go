@@ -0,0 +1,249 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Jumlah reference yang dihidrasi dari MongoDB per batch saat ekspor.
+const exportBatchSize = 500
+
+const (
+	exportTimeLayout = "2006-01-02 15:04:05"
+	exportDateLayout = "2006-01-02"
+)
+
+var achievementExportColumns = []string{
+	"reference_id", "status", "student_nim", "student_name", "study_program", "year_of_entry", "advisor_name",
+	"achievement_type", "title", "description", "tags", "points", "points_excluded",
+	"competition_name", "competition_level", "rank", "medal_type",
+	"publication_type", "publication_title", "authors", "publisher", "issn",
+	"organization_name", "position", "period_start", "period_end",
+	"certification_name", "issued_by", "certification_number", "valid_until",
+	"event_date", "location", "organizer", "score", "custom_fields",
+	"created_at", "submitted_at", "verified_at", "verified_by", "rejection_note", "document_missing",
+}
+
+// exportWriter adalah penulis baris ekspor untuk CSV maupun XLSX.
+type exportWriter interface {
+	WriteHeader(values []string) error
+	WriteRow(values []interface{}) error
+	Flush() error
+	Close() error
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) (*csvExportWriter, error) {
+	// BOM agar Excel membaca CSV sebagai UTF-8.
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvExportWriter) WriteHeader(values []string) error {
+	return c.w.Write(values)
+}
+
+// csvCell mengubah nilai menjadi teks. Teks yang diawali karakter formula
+// diberi tanda kutip agar tidak dieksekusi spreadsheet.
+func csvCell(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if val != "" && strings.ContainsRune("=+-@\t\r", rune(val[0])) {
+			return "'" + val
+		}
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func (c *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = csvCell(v)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	return c.Flush()
+}
+
+func formatExportTime(t *time.Time, layout string) interface{} {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t.Format(layout)
+}
+
+func achievementExportValues(row model.AchievementExportRow) []interface{} {
+	values := []interface{}{
+		row.ReferenceID, row.Status, row.StudentNIM, row.StudentName, row.StudyProgram, nil, row.AdvisorName,
+	}
+	if row.YearOfEntry > 0 {
+		values[5] = row.YearOfEntry
+	}
+
+	doc := row.Achievement
+	if doc == nil {
+		doc = &model.Achievement{}
+	}
+	d := doc.Details
+
+	var periodStart, periodEnd interface{}
+	if d.Period != nil {
+		periodStart = formatExportTime(&d.Period.Start, exportDateLayout)
+		periodEnd = formatExportTime(&d.Period.End, exportDateLayout)
+	}
+	var rank, score interface{}
+	if d.Rank > 0 {
+		rank = d.Rank
+	}
+	if d.Score != 0 {
+		score = d.Score
+	}
+	var custom interface{}
+	if len(d.CustomFields) > 0 {
+		if b, err := json.Marshal(d.CustomFields); err == nil {
+			custom = string(b)
+		}
+	}
+	pointsExcluded, missing := "", ""
+	if doc.PointsExcluded {
+		pointsExcluded = "ya"
+	}
+	if row.Achievement == nil {
+		missing = "ya"
+	}
+
+	createdAt := row.CreatedAt
+	return append(values,
+		doc.AchievementType, doc.Title, doc.Description, strings.Join(doc.Tags, "; "), doc.Points, pointsExcluded,
+		d.CompetitionName, d.CompetitionLevel, rank, d.MedalType,
+		d.PublicationType, d.PublicationTitle, strings.Join(d.Authors, "; "), d.Publisher, d.ISSN,
+		d.OrganizationName, d.Position, periodStart, periodEnd,
+		d.CertificationName, d.IssuedBy, d.CertificationNumber, formatExportTime(d.ValidUntil, exportDateLayout),
+		formatExportTime(d.EventDate, exportDateLayout), d.Location, d.Organizer, score, custom,
+		formatExportTime(&createdAt, exportTimeLayout), formatExportTime(row.SubmittedAt, exportTimeLayout),
+		formatExportTime(row.VerifiedAt, exportTimeLayout), row.VerifierName, row.RejectionNote, missing,
+	)
+}
+
+// writeAchievementExport mengalirkan seluruh baris sesuai filter ke writer.
+// Setiap batch dihidrasi dengan satu query MongoDB lalu langsung di-flush.
+func (s *AchievementService) writeAchievementExport(out exportWriter, filter model.AchievementListFilter) error {
+	if err := out.WriteHeader(achievementExportColumns); err != nil {
+		return err
+	}
+
+	err := s.PGRepo.StreamExportRows(filter, exportBatchSize, func(batch []model.AchievementExportRow) error {
+		ids := make([]string, len(batch))
+		for i := range batch {
+			ids[i] = batch[i].MongoID
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), hydrateTimeout)
+		docs, err := s.Mongo.FindByHexIDs(ctx, ids, nil)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if doc, ok := docs[batch[i].MongoID]; ok {
+				doc = doc.ForReference(batch[i].ReferenceID)
+				batch[i].Achievement = &doc
+			}
+			if err := out.WriteRow(achievementExportValues(batch[i])); err != nil {
+				return err
+			}
+		}
+		return out.Flush()
+	})
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// ExportAchievementsService mengekspor daftar prestasi dengan filter yang sama
+// seperti endpoint daftar ke CSV atau XLSX. Paginasi diabaikan.
+func (s *AchievementService) ExportAchievementsService(c *fiber.Ctx) error {
+	role := getUserRole(c)
+	userID := getUserID(c)
+
+	format := strings.ToLower(c.Query("format", model.ExportFormatCSV))
+	filter, docFilter, fieldErrs := parseAchievementListQuery(c, role)
+	if format != model.ExportFormatCSV && format != model.ExportFormatXLSX {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "format", Message: "harus csv atau xlsx"})
+	}
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: fieldErrs})
+	}
+
+	scopeListFilter(&filter, role, userID)
+
+	if !docFilter.IsEmpty() {
+		ctx, cancel := context.WithTimeout(context.Background(), hydrateTimeout)
+		ids, err := s.Mongo.FindIDs(ctx, docFilter)
+		cancel()
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data"})
+		}
+		filter.MongoIDs = ids
+		filter.RestrictToMongoIDs = true
+	}
+
+	filename := "prestasi-" + time.Now().Format("20060102-150405") + "." + format
+	if format == model.ExportFormatXLSX {
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	} else {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var out exportWriter
+		var err error
+		if format == model.ExportFormatXLSX {
+			out, err = utils.NewXLSXWriter(w, "Prestasi")
+		} else {
+			out, err = newCSVExportWriter(w)
+		}
+		if err == nil {
+			err = s.writeAchievementExport(out, filter)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		// Status sudah terkirim, jadi kegagalan di tengah hanya bisa dicatat.
+		if err != nil {
+			log.Printf("Achievement export (%s) by %s aborted: %v", format, userID, err)
+		}
+	})
+	return nil
+}
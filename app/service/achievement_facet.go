@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go-fiber/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// facetRow adalah satu reference beserta nilai facet dari dokumennya.
+type facetRow struct {
+	key model.ReferenceFacetKey
+	doc model.DocumentFacetFields
+}
+
+// countFacet menghitung jumlah reference per nilai, diurutkan dari yang
+// terbanyak.
+func countFacet(rows []facetRow, value func(facetRow) string) []model.FacetBucket {
+	counts := map[string]int{}
+	for _, r := range rows {
+		if v := value(r); v != "" {
+			counts[v]++
+		}
+	}
+
+	out := make([]model.FacetBucket, 0, len(counts))
+	for v, n := range counts {
+		out = append(out, model.FacetBucket{Value: v, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// FacetAchievementsService mengembalikan satu halaman prestasi beserta jumlah
+// per tipe, tingkat, medali, status, tahun, dan program studi untuk seluruh
+// hasil yang cocok dengan filter.
+func (s *AchievementService) FacetAchievementsService(c *fiber.Ctx) error {
+	role := getUserRole(c)
+	userID := getUserID(c)
+
+	filter, docFilter, fieldErrs := parseAchievementListQuery(c, role)
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: fieldErrs})
+	}
+	scopeListFilter(&filter, role, userID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), hydrateTimeout)
+	defer cancel()
+
+	if !docFilter.IsEmpty() {
+		ids, err := s.Mongo.FindIDs(ctx, docFilter)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data"})
+		}
+		filter.MongoIDs = ids
+		filter.RestrictToMongoIDs = true
+	}
+
+	keys, err := s.PGRepo.ListFacetKeys(filter)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menghitung facet"})
+	}
+
+	ids := make([]string, 0, len(keys))
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if !seen[k.MongoID] {
+			seen[k.MongoID] = true
+			ids = append(ids, k.MongoID)
+		}
+	}
+
+	docs, err := s.Mongo.FacetFields(ctx, ids)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menghitung facet"})
+	}
+
+	rows := make([]facetRow, len(keys))
+	for i, k := range keys {
+		rows[i] = facetRow{key: k, doc: docs[k.MongoID]}
+	}
+	facets := model.AchievementFacets{
+		AchievementType:  countFacet(rows, func(r facetRow) string { return r.doc.AchievementType }),
+		CompetitionLevel: countFacet(rows, func(r facetRow) string { return r.doc.CompetitionLevel }),
+		MedalType:        countFacet(rows, func(r facetRow) string { return r.doc.MedalType }),
+		Status:           countFacet(rows, func(r facetRow) string { return r.key.Status }),
+		Year:             countFacet(rows, func(r facetRow) string { return r.doc.Year }),
+		StudyProgram:     countFacet(rows, func(r facetRow) string { return r.key.StudyProgram }),
+	}
+	// Tahun diurutkan dari yang terbaru, bukan dari jumlah terbanyak.
+	sort.Slice(facets.Year, func(i, j int) bool { return facets.Year[i].Value > facets.Year[j].Value })
+
+	list, total, err := s.PGRepo.ListReferences(filter)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data"})
+	}
+
+	missing, err := hydrateReferences(ctx, s.Mongo, list)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data prestasi"})
+	}
+
+	now := time.Now()
+	for i := range list {
+		applySLA(&list[i], now)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   model.AchievementFacetResponse{Items: list, Facets: facets},
+		Meta: model.AchievementListMeta{
+			PageMeta:         model.NewPageMeta(filter.Page, filter.Limit, total),
+			MissingDocuments: missing,
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Batas waktu satu query hidrasi daftar prestasi ke MongoDB.
+const hydrateTimeout = 10 * time.Second
+
+// hydrateReferences mengisi dokumen MongoDB untuk setiap reference dengan satu
+// query. Reference yang dokumennya tidak ditemukan ditandai DocumentMissing
+// dan mongo_id-nya dikembalikan.
+func hydrateReferences(ctx context.Context, repo *repository.AchievementMongoRepo, items []model.AchievementDetailResponse) ([]string, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = items[i].MongoID
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hydrateTimeout)
+	defer cancel()
+
+	docs, err := repo.FindByHexIDs(ctx, ids, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for i := range items {
+		doc, ok := docs[items[i].MongoID]
+		if !ok {
+			items[i].DocumentMissing = true
+			missing = append(missing, items[i].MongoID)
+			continue
+		}
+		items[i].Achievement = doc.ForReference(items[i].ReferenceID)
+	}
+
+	if len(missing) > 0 {
+		log.Printf("Achievement hydration: %d reference(s) point to missing Mongo documents: %v", len(missing), missing)
+	}
+	return missing, nil
+}
+
+// findTitles mengambil judul dan tipe prestasi untuk banyak dokumen sekaligus.
+func findTitles(ctx context.Context, repo *repository.AchievementMongoRepo, hexIDs []string) (map[string]model.Achievement, error) {
+	ctx, cancel := context.WithTimeout(ctx, hydrateTimeout)
+	defer cancel()
+	return repo.FindByHexIDs(ctx, hexIDs, bson.M{"title": 1, "achievementType": 1})
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	listDateLayout   = "2006-01-02"
+)
+
+var achievementStatuses = []string{"draft", "submitted", "verified", "rejected", "deleted"}
+
+// splitQueryList memecah parameter berformat "a,b,c" dan membuang nilai kosong.
+func splitQueryList(raw string) []string {
+	var out []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// parseListDate membaca tanggal YYYY-MM-DD. Untuk batas akhir (endOfDay)
+// nilai digeser satu hari agar tanggal tersebut ikut terhitung.
+func parseListDate(c *fiber.Ctx, field string, endOfDay bool, errs *[]model.FieldError) *time.Time {
+	raw := c.Query(field)
+	if raw == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation(listDateLayout, raw, time.Local)
+	if err != nil {
+		*errs = append(*errs, model.FieldError{Field: field, Message: "format tanggal harus YYYY-MM-DD"})
+		return nil
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t
+}
+
+// parseStatusQuery membaca parameter status. Status deleted hanya untuk Admin.
+func parseStatusQuery(c *fiber.Ctx, role string, errs *[]model.FieldError) []string {
+	var out []string
+	for _, st := range splitQueryList(c.Query("status")) {
+		st = strings.ToLower(st)
+		if !contains(achievementStatuses, st) {
+			*errs = append(*errs, model.FieldError{Field: "status", Message: "harus salah satu dari: " + strings.Join(achievementStatuses, ", ")})
+			continue
+		}
+		if st == "deleted" && role != "Admin" {
+			*errs = append(*errs, model.FieldError{Field: "status", Message: "status deleted hanya dapat dilihat Admin"})
+			continue
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// scopeListFilter membatasi filter sesuai role: mahasiswa hanya melihat
+// miliknya sendiri dan dosen wali hanya melihat mahasiswa bimbingannya.
+func scopeListFilter(f *model.AchievementListFilter, role, userID string) {
+	switch role {
+	case "Admin":
+	case "Dosen Wali":
+		f.AdvisorID = userID
+	default:
+		f.StudentID = userID
+	}
+}
+
+func parsePositiveQuery(c *fiber.Ctx, field string, def int, errs *[]model.FieldError) int {
+	raw := c.Query(field)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		*errs = append(*errs, model.FieldError{Field: field, Message: "harus bilangan bulat positif"})
+		return def
+	}
+	return n
+}
+
+func checkDateRange(from, to *time.Time, field string, errs *[]model.FieldError) {
+	if from != nil && to != nil && !from.Before(*to) {
+		*errs = append(*errs, model.FieldError{Field: field, Message: "tanggal akhir tidak boleh sebelum tanggal awal"})
+	}
+}
+
+// parseAchievementListQuery membaca query string daftar prestasi menjadi filter
+// PostgreSQL dan filter dokumen MongoDB.
+func parseAchievementListQuery(c *fiber.Ctx, role string) (model.AchievementListFilter, model.AchievementDocFilter, []model.FieldError) {
+	var errs []model.FieldError
+	f := model.AchievementListFilter{
+		StudentID:    strings.TrimSpace(c.Query("student_id")),
+		StudyProgram: strings.TrimSpace(c.Query("study_program")),
+		Sort:         c.Query("sort", "created_at"),
+		Order:        strings.ToLower(c.Query("order", "desc")),
+	}
+
+	f.Statuses = parseStatusQuery(c, role, &errs)
+
+	if !repository.IsAchievementListSortable(f.Sort) {
+		errs = append(errs, model.FieldError{Field: "sort", Message: "harus salah satu dari: created_at, updated_at, submitted_at, verified_at, status"})
+	}
+	if f.Order != "asc" && f.Order != "desc" {
+		errs = append(errs, model.FieldError{Field: "order", Message: "harus asc atau desc"})
+	}
+
+	f.Page = parsePositiveQuery(c, "page", 1, &errs)
+	f.Limit = parsePositiveQuery(c, "limit", defaultListLimit, &errs)
+	if f.Limit > maxListLimit {
+		f.Limit = maxListLimit
+	}
+
+	f.CreatedFrom = parseListDate(c, "created_from", false, &errs)
+	f.CreatedTo = parseListDate(c, "created_to", true, &errs)
+	checkDateRange(f.CreatedFrom, f.CreatedTo, "created_to", &errs)
+	f.SubmittedFrom = parseListDate(c, "submitted_from", false, &errs)
+	f.SubmittedTo = parseListDate(c, "submitted_to", true, &errs)
+	checkDateRange(f.SubmittedFrom, f.SubmittedTo, "submitted_to", &errs)
+
+	doc := model.AchievementDocFilter{
+		AchievementType:  strings.TrimSpace(c.Query("achievement_type")),
+		CompetitionLevel: strings.TrimSpace(c.Query("competition_level")),
+		MedalType:        strings.TrimSpace(c.Query("medal_type")),
+		Tags:             splitQueryList(c.Query("tags")),
+		EventFrom:        parseListDate(c, "event_from", false, &errs),
+		EventTo:          parseListDate(c, "event_to", true, &errs),
+		Year:             parsePositiveQuery(c, "year", 0, &errs),
+	}
+	checkDateRange(doc.EventFrom, doc.EventTo, "event_to", &errs)
+
+	return f, doc, errs
+}
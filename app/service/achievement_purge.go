@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-fiber/app/model"
+	"go-fiber/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const uploadRoot = "uploads/achievements"
+
+func restoreGraceDays() int {
+	return utils.GetEnvInt("ACHIEVEMENT_RESTORE_GRACE_DAYS", 30)
+}
+
+func purgeAfterDays() int {
+	days := utils.GetEnvInt("ACHIEVEMENT_PURGE_AFTER_DAYS", 90)
+	if grace := restoreGraceDays(); days < grace {
+		return grace
+	}
+	return days
+}
+
+func (s *AchievementService) RestoreAchievementService(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	role := getUserRole(c)
+	refID := c.Params("id")
+
+	ref, err := s.PGRepo.GetReference(refID)
+	if err != nil || (role != "Admin" && ref.StudentID != userID) {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Reference tidak ditemukan"})
+	}
+
+	if ref.ReferenceStatus != "deleted" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Hanya prestasi yang dihapus yang bisa dipulihkan"})
+	}
+
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memulihkan prestasi"})
+	}
+
+	ok, err := s.PGRepo.RestoreReference(tx, refID, userID, restoreGraceDays())
+	if err != nil {
+		tx.Rollback()
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memulihkan prestasi"})
+	}
+	if !ok {
+		tx.Rollback()
+		return c.Status(410).JSON(model.APIResponse{Status: "error", Error: "Masa tenggang pemulihan sudah lewat"})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memulihkan prestasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Message: "restored"})
+}
+
+func (s *AchievementService) PurgeAchievementService(c *fiber.Ctx) error {
+	result := s.purgeReference(context.Background(), c.Params("id"))
+
+	switch result.Status {
+	case "not_found":
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: result.Error, Data: result})
+	case "invalid":
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: result.Error, Data: result})
+	case "error":
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: result.Error, Data: result})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Message: "purged", Data: result})
+}
+
+func (s *AchievementService) BulkPurgeService(c *fiber.Ctx) error {
+	var req struct {
+		OlderThanDays int `json:"older_than_days"`
+		Limit         int `json:"limit"`
+	}
+	_ = c.BodyParser(&req)
+
+	if req.OlderThanDays == 0 {
+		req.OlderThanDays = purgeAfterDays()
+	}
+	if req.OlderThanDays < restoreGraceDays() {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "older_than_days tidak boleh kurang dari masa tenggang pemulihan"})
+	}
+	if req.Limit <= 0 || req.Limit > 1000 {
+		req.Limit = 500
+	}
+
+	results, err := s.PurgeDeletedAchievements(context.Background(), req.OlderThanDays, req.Limit)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data prestasi terhapus"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: results})
+}
+
+// PurgeDeletedAchievements menghapus permanen prestasi yang sudah dihapus lebih
+// lama dari olderThanDays. Dipakai oleh endpoint admin dan job terjadwal.
+func (s *AchievementService) PurgeDeletedAchievements(ctx context.Context, olderThanDays, limit int) ([]model.PurgeResult, error) {
+	ids, err := s.PGRepo.ListDeletedBefore(olderThanDays, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.PurgeResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, s.purgeReference(ctx, id))
+	}
+	return results, nil
+}
+
+// purgeReference menghapus reference, dokumen MongoDB (jika tidak dipakai
+// reference lain) dan file lampirannya. Penghapusan MongoDB dilakukan sebelum
+// commit PostgreSQL dan dikompensasi jika commit gagal.
+func (s *AchievementService) purgeReference(ctx context.Context, refID string) model.PurgeResult {
+	result := model.PurgeResult{ReferenceID: refID}
+
+	ref, err := s.PGRepo.GetReference(refID)
+	if err != nil {
+		result.Status, result.Error = "not_found", "Reference tidak ditemukan"
+		return result
+	}
+	if ref.ReferenceStatus != "deleted" {
+		result.Status, result.Error = "invalid", "Hanya prestasi berstatus deleted yang bisa dihapus permanen"
+		return result
+	}
+
+	ach, err := s.Mongo.FindByHexID(ctx, ref.MongoID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		result.Status, result.Error = "error", "Gagal mengambil data MongoDB"
+		return result
+	}
+
+	tx, err := s.PG.Begin()
+	if err != nil {
+		result.Status, result.Error = "error", "Gagal menghapus permanen"
+		return result
+	}
+
+	remaining, err := s.PGRepo.DeleteReference(tx, refID, ref.MongoID)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			result.Status, result.Error = "invalid", "Status prestasi sudah berubah"
+		} else {
+			result.Status, result.Error = "error", "Gagal menghapus reference"
+		}
+		return result
+	}
+
+	if ach != nil {
+		if remaining == 0 {
+			err = s.Mongo.DeleteByHexID(ctx, ref.MongoID)
+		} else if ach.StudentID != ref.StudentID {
+			err = s.Mongo.RemoveTeamMember(ctx, ref.MongoID, ref.StudentID)
+		}
+		if err != nil {
+			tx.Rollback()
+			result.Status, result.Error = "error", "Gagal menghapus dokumen MongoDB"
+			return result
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		if ach != nil && remaining == 0 {
+			_ = s.Mongo.Restore(ctx, *ach)
+		}
+		result.Status, result.Error = "error", "Gagal menghapus permanen"
+		return result
+	}
+
+	result.Status = "purged"
+	result.DocumentDeleted = ach != nil && remaining == 0
+
+	if remaining == 0 {
+		// Snapshot revisi adalah salinan penuh dokumen, jadi ikut dihapus.
+		// Jika gagal, sisanya dilaporkan oleh rekonsiliasi.
+		deleted, err := s.Revisions.DeleteByAchievement(ctx, ref.MongoID)
+		if err != nil {
+			log.Printf("Failed to delete revisions of %s: %v", ref.MongoID, err)
+		}
+		result.RevisionsDeleted = deleted
+
+		if ach != nil {
+			for _, att := range ach.Attachments {
+				if removeUploadedFile(att.FileUrl) {
+					result.FilesRemoved++
+				}
+			}
+		}
+		_ = os.RemoveAll(filepath.Join(uploadRoot, refID))
+	} else if ach == nil || !hasAttachmentsIn(ach.Attachments, refID) {
+		_ = os.RemoveAll(filepath.Join(uploadRoot, refID))
+	}
+
+	return result
+}
+
+// removeUploadedFile hanya menghapus file yang berada di bawah direktori upload
+// prestasi, lalu membersihkan direktori reference jika sudah kosong.
+func removeUploadedFile(path string) bool {
+	clean := filepath.Clean(path)
+	if !strings.HasPrefix(clean, filepath.Clean(uploadRoot)+string(os.PathSeparator)) {
+		return false
+	}
+	if err := os.Remove(clean); err != nil {
+		return false
+	}
+	_ = os.Remove(filepath.Dir(clean))
+	return true
+}
+
+func hasAttachmentsIn(atts []model.Attachment, refID string) bool {
+	dir := filepath.Join(uploadRoot, refID) + string(os.PathSeparator)
+	for _, att := range atts {
+		if strings.HasPrefix(filepath.Clean(att.FileUrl), dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"go-fiber/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxBatchReviewItems = 100
+
+type reviewError struct {
+	Status  int
+	Message string
+}
+
+func (e *reviewError) Error() string {
+	return e.Message
+}
+
+// authorizeReview menerapkan aturan akses verifikasi: hanya dosen wali dari
+// mahasiswa pemilik reference yang boleh memverifikasi atau menolak, kecuali
+// prestasi yang sudah dieskalasi ke antrean admin program studinya.
+func (s *AchievementService) authorizeReview(reviewerID, role, refID string) (*model.AchievementDetailResponse, *reviewError) {
+	if role == "Admin" {
+		ref, err := s.PGRepo.GetReferenceDetail(refID)
+		if err != nil {
+			return nil, &reviewError{Status: 404, Message: "Reference tidak ditemukan"}
+		}
+		if ref.EscalatedAt == nil {
+			return nil, &reviewError{Status: 403, Message: "Admin hanya dapat mereview prestasi yang sudah dieskalasi"}
+		}
+		ok, err := s.PGRepo.AdminHandlesStudent(reviewerID, ref.StudentID)
+		if err != nil {
+			return nil, &reviewError{Status: 500, Message: "Gagal memeriksa program studi admin"}
+		}
+		if !ok {
+			return nil, &reviewError{Status: 403, Message: "Prestasi ini ditangani admin program studi lain"}
+		}
+		return ref, nil
+	}
+
+	if role != "Dosen Wali" {
+		return nil, &reviewError{Status: 403, Message: "Akses ditolak"}
+	}
+
+	ref, err := s.PGRepo.GetReferenceWithAdvisor(refID, reviewerID)
+	if err != nil || ref.AdvisorID != reviewerID {
+		return nil, &reviewError{Status: 403, Message: "Anda bukan dosen wali mahasiswa ini"}
+	}
+
+	return ref, nil
+}
+
+// verifyOne memverifikasi satu reference. Perubahan status dan event outbox
+// untuk points di MongoDB disimpan dalam satu transaksi PostgreSQL.
+func (s *AchievementService) verifyOne(reviewerID, role, refID string, points int, expected *entityVersion) (int64, *reviewError) {
+	ref, rerr := s.authorizeReview(reviewerID, role, refID)
+	if rerr != nil {
+		return 0, rerr
+	}
+
+	if points <= 0 {
+		return 0, &reviewError{Status: 400, Message: "Points harus lebih dari 0"}
+	}
+
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return 0, &reviewError{Status: 500, Message: "Gagal verifikasi"}
+	}
+
+	status, version, err := s.PGRepo.LockReference(tx, refID)
+	if err != nil {
+		tx.Rollback()
+		return 0, &reviewError{Status: 500, Message: "Gagal verifikasi"}
+	}
+	if status != "submitted" {
+		tx.Rollback()
+		return 0, &reviewError{Status: 400, Message: "Prestasi hanya bisa diverifikasi setelah disubmit"}
+	}
+	if rerr := s.checkDocumentVersion(ref.MongoID, version, expected); rerr != nil {
+		tx.Rollback()
+		return 0, rerr
+	}
+	// Dokumen tim boleh sudah terkunci oleh verifikasi anggota lain karena
+	// points disimpan per reference; yang dijaga adalah points reference ini.
+	if rerr := s.ensurePointsUnset(ref.MongoID, refID); rerr != nil {
+		tx.Rollback()
+		return 0, rerr
+	}
+
+	newVersion, err := s.PGRepo.VerifyReference(tx, refID, reviewerID)
+	if err != nil {
+		tx.Rollback()
+		return 0, &reviewError{Status: 500, Message: "Gagal verifikasi"}
+	}
+
+	verified := []model.ReferenceVersion{{ID: refID, Version: newVersion}}
+	cascade, err := s.cascadeTeamReview(ref)
+	if err != nil {
+		tx.Rollback()
+		return 0, &reviewError{Status: 500, Message: "Gagal mengambil data MongoDB"}
+	}
+	if cascade {
+		team, err := s.PGRepo.VerifyTeamReferences(tx, ref.MongoID, refID, reviewerID)
+		if err != nil {
+			tx.Rollback()
+			return 0, &reviewError{Status: 500, Message: "Gagal verifikasi anggota tim"}
+		}
+		verified = append(verified, team...)
+	}
+
+	// Points disimpan per reference, sehingga setiap anggota yang ikut
+	// diverifikasi mendapat event sendiri.
+	eventIDs := make([]string, len(verified))
+	payloads := make([]model.SetPointsPayload, len(verified))
+	for i, v := range verified {
+		payloads[i] = model.SetPointsPayload{
+			ReferenceID:     v.ID,
+			MongoID:         ref.MongoID,
+			Points:          points,
+			RefVersion:      v.Version,
+			Status:          "verified",
+			ActorID:         reviewerID,
+			RevertOnFailure: true,
+		}
+		eventIDs[i], err = s.Outbox.Enqueue(tx, model.OutboxAchievementSetPoints, v.ID, payloads[i], outboxInlineDelaySeconds)
+		if err != nil {
+			tx.Rollback()
+			return 0, &reviewError{Status: 500, Message: "Gagal verifikasi"}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, &reviewError{Status: 500, Message: "Gagal verifikasi"}
+	}
+
+	for i, eventID := range eventIDs {
+		if err := s.runOutboxInline(context.Background(), eventID, model.OutboxAchievementSetPoints, payloads[i]); err != nil {
+			log.Printf("Points for %s deferred to outbox: %v", verified[i].ID, err)
+		}
+	}
+
+	return int64(len(verified) - 1), nil
+}
+
+// checkDocumentVersion membandingkan versi reference yang sudah dikunci dan
+// versi dokumen MongoDB saat ini dengan nilai If-Match.
+func (s *AchievementService) checkDocumentVersion(mongoHex string, refVersion int, expected *entityVersion) *reviewError {
+	if expected == nil {
+		return nil
+	}
+	if !expected.matchesRef(refVersion) {
+		return &reviewError{Status: 412, Message: versionMismatchMessage}
+	}
+
+	ach, err := s.Mongo.FindByHexID(context.Background(), mongoHex)
+	if err != nil {
+		return &reviewError{Status: 500, Message: "Gagal mengambil data MongoDB"}
+	}
+	if !expected.matchesDoc(ach.Version) {
+		return &reviewError{Status: 412, Message: versionMismatchMessage}
+	}
+	return nil
+}
+
+// ensurePointsUnset menolak verifikasi jika points reference masih tercatat di
+// MongoDB, misalnya reset setelah reopen belum selesai diproses outbox.
+func (s *AchievementService) ensurePointsUnset(mongoHex, refID string) *reviewError {
+	ach, err := s.Mongo.FindByHexID(context.Background(), mongoHex)
+	if err != nil {
+		return &reviewError{Status: 500, Message: "Gagal mengambil data MongoDB"}
+	}
+	if ach.ReferencePoints[refID] > 0 {
+		return &reviewError{Status: 409, Message: "Points prestasi ini masih tercatat dan tidak bisa diubah, coba lagi setelah proses sebelumnya selesai"}
+	}
+	return nil
+}
+
+func (s *AchievementService) rejectOne(reviewerID, role, refID, note string, expected *entityVersion) *reviewError {
+	ref, rerr := s.authorizeReview(reviewerID, role, refID)
+	if rerr != nil {
+		return rerr
+	}
+
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return &reviewError{Status: 500, Message: "Gagal reject"}
+	}
+
+	status, version, err := s.PGRepo.LockReference(tx, refID)
+	if err != nil {
+		tx.Rollback()
+		return &reviewError{Status: 500, Message: "Gagal reject"}
+	}
+	if status != "submitted" {
+		tx.Rollback()
+		return &reviewError{Status: 400, Message: "Prestasi hanya bisa ditolak setelah disubmit"}
+	}
+	if rerr := s.checkDocumentVersion(ref.MongoID, version, expected); rerr != nil {
+		tx.Rollback()
+		return rerr
+	}
+
+	if err := s.PGRepo.RejectReference(tx, refID, reviewerID, note); err != nil {
+		tx.Rollback()
+		return &reviewError{Status: 500, Message: "Gagal reject"}
+	}
+
+	cascade, err := s.cascadeTeamReview(ref)
+	if err != nil {
+		tx.Rollback()
+		return &reviewError{Status: 500, Message: "Gagal mengambil data MongoDB"}
+	}
+	if cascade {
+		if _, err := s.PGRepo.RejectTeamReferences(tx, ref.MongoID, refID, reviewerID, note); err != nil {
+			tx.Rollback()
+			return &reviewError{Status: 500, Message: "Gagal reject anggota tim"}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &reviewError{Status: 500, Message: "Gagal reject"}
+	}
+
+	return nil
+}
+
+// StartReviewService menandai bahwa reviewer mulai mereview prestasi yang
+// disubmit. Setelah itu mahasiswa tidak lagi bisa menarik kembali submit-nya.
+func (s *AchievementService) StartReviewService(c *fiber.Ctx) error {
+	reviewerID := getUserID(c)
+	refID := c.Params("id")
+
+	ref, rerr := s.authorizeReview(reviewerID, getUserRole(c), refID)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+	if ref.ReferenceStatus != "submitted" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Review hanya bisa dimulai untuk prestasi yang sudah disubmit"})
+	}
+
+	// MarkReviewStarted hanya mengubah reference yang masih submitted, jadi
+	// status yang berubah setelah pemeriksaan di atas tidak ikut ditandai.
+	if err := s.PGRepo.MarkReviewStarted(refID, reviewerID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memulai review"})
+	}
+
+	ref, err := s.PGRepo.GetReference(refID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memulai review"})
+	}
+	if ref.ReferenceStatus != "submitted" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Review hanya bisa dimulai untuk prestasi yang sudah disubmit"})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "Review dimulai",
+		Data: fiber.Map{
+			"reference_id":      ref.ReferenceID,
+			"review_started_at": ref.ReviewStartedAt,
+		},
+	})
+}
+
+// ReopenAchievementService membuka kembali prestasi yang sudah diverifikasi
+// agar direview ulang. Points dikosongkan dan dokumen tidak lagi terkunci.
+func (s *AchievementService) ReopenAchievementService(c *fiber.Ctx) error {
+	if getUserRole(c) != "Admin" {
+		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: "Akses ditolak"})
+	}
+	adminID := getUserID(c)
+	refID := c.Params("id")
+
+	var req model.ReopenAchievementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Body request tidak valid"})
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Validasi gagal", Data: []model.FieldError{{Field: "reason", Message: "wajib diisi"}}})
+	}
+
+	ref, err := s.PGRepo.GetReference(refID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Reference tidak ditemukan"})
+	}
+	if ref.ReferenceStatus != "verified" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Hanya prestasi berstatus verified yang bisa dibuka kembali"})
+	}
+
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuka kembali prestasi"})
+	}
+
+	status, err := s.PGRepo.LockReferenceStatus(tx, refID)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuka kembali prestasi"})
+	}
+	if status != "verified" {
+		tx.Rollback()
+		return c.Status(409).JSON(model.APIResponse{Status: "error", Error: "Status prestasi sudah berubah, muat ulang data"})
+	}
+
+	// Prestasi tim berbagi satu dokumen, jadi semua reference verified ikut dibuka.
+	reopened, err := s.PGRepo.ReopenReferences(tx, ref.MongoID, adminID, req.Reason)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuka kembali prestasi"})
+	}
+
+	eventIDs := make([]string, len(reopened))
+	payloads := make([]model.SetPointsPayload, len(reopened))
+	for i, r := range reopened {
+		payloads[i] = model.SetPointsPayload{
+			ReferenceID: r.ReferenceID,
+			MongoID:     ref.MongoID,
+			Points:      0,
+			RefVersion:  r.Version,
+			Status:      "submitted",
+			ActorID:     adminID,
+		}
+		eventIDs[i], err = s.Outbox.Enqueue(tx, model.OutboxAchievementSetPoints, r.ReferenceID, payloads[i], outboxInlineDelaySeconds)
+		if err != nil {
+			tx.Rollback()
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuka kembali prestasi"})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuka kembali prestasi"})
+	}
+
+	for i, eventID := range eventIDs {
+		if err := s.runOutboxInline(context.Background(), eventID, model.OutboxAchievementSetPoints, payloads[i]); err != nil {
+			log.Printf("Points reset for %s deferred to outbox: %v", reopened[i].ReferenceID, err)
+		}
+	}
+
+	for _, r := range reopened {
+		id := r.ReferenceID
+		message := "Prestasi dibuka kembali oleh admin untuk direview ulang. Alasan: " + req.Reason
+		notify(s.Notif, r.StudentID, model.NotificationAchievementReopened, "Prestasi dibuka kembali", message, &id)
+		notify(s.Notif, r.AdvisorID, model.NotificationAchievementReopened, "Prestasi perlu direview ulang", message, &id)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "reopened",
+		Data:    fiber.Map{"references": reopened},
+	})
+}
+
+func (s *AchievementService) BatchReviewService(c *fiber.Ctx) error {
+	reviewerID := getUserID(c)
+	role := getUserRole(c)
+
+	var req model.BatchReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Body request tidak valid"})
+	}
+
+	if len(req.Items) == 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "items wajib diisi"})
+	}
+	if len(req.Items) > maxBatchReviewItems {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Maksimal 100 item per batch"})
+	}
+
+	resp := model.BatchReviewResponse{Total: len(req.Items), Results: make([]model.BatchReviewResult, 0, len(req.Items))}
+	seen := map[string]bool{}
+
+	for _, item := range req.Items {
+		result := model.BatchReviewResult{ReferenceID: item.ReferenceID, Action: item.Action}
+
+		var rerr *reviewError
+		switch {
+		case item.ReferenceID == "":
+			rerr = &reviewError{Status: 400, Message: "reference_id wajib diisi"}
+		case seen[item.ReferenceID]:
+			rerr = &reviewError{Status: 400, Message: "reference_id duplikat dalam batch"}
+		case item.Action != "verify" && item.Action != "reject":
+			rerr = &reviewError{Status: 400, Message: "action harus verify atau reject"}
+		default:
+			var expected *entityVersion
+			if expected, rerr = parseETag(item.IfMatch); rerr != nil {
+				break
+			}
+			if item.Action == "verify" {
+				result.TeamReferencesVerified, rerr = s.verifyOne(reviewerID, role, item.ReferenceID, item.Points, expected)
+				if rerr == nil {
+					result.Points = item.Points
+				}
+			} else {
+				rerr = s.rejectOne(reviewerID, role, item.ReferenceID, item.Note, expected)
+			}
+		}
+		seen[item.ReferenceID] = true
+
+		if rerr != nil {
+			result.Status = "error"
+			result.HTTPStatus = rerr.Status
+			result.Error = rerr.Message
+			resp.Failed++
+		} else {
+			result.Status = "success"
+			result.HTTPStatus = 200
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: resp})
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go-fiber/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	searchMinQueryLength = 2
+	searchSnippetRunes   = 120
+	searchSnippetLead    = 40
+)
+
+// searchTerms mengambil kata dan frasa ("...") dari query untuk highlight.
+// Kata yang diawali "-" adalah negasi di MongoDB sehingga diabaikan.
+func searchTerms(q string) []string {
+	var terms []string
+	for i, part := range strings.Split(q, `"`) {
+		if i%2 == 1 {
+			if p := strings.TrimSpace(part); p != "" {
+				terms = append(terms, p)
+			}
+			continue
+		}
+		for _, w := range strings.Fields(part) {
+			if !strings.HasPrefix(w, "-") {
+				terms = append(terms, w)
+			}
+		}
+	}
+	// Term terpanjang dicocokkan lebih dulu agar frasa tidak terpotong kata pendek.
+	sort.SliceStable(terms, func(i, j int) bool {
+		return utf8.RuneCountInString(terms[i]) > utf8.RuneCountInString(terms[j])
+	})
+	return terms
+}
+
+func lowerRunes(s string) []rune {
+	rs := []rune(s)
+	for i, r := range rs {
+		rs[i] = unicode.ToLower(r)
+	}
+	return rs
+}
+
+func runesHasPrefix(s []rune, at int, prefix []rune) bool {
+	if at+len(prefix) > len(s) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[at+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAt mengembalikan panjang term yang cocok pada posisi at, atau 0.
+func matchAt(lower []rune, at int, terms [][]rune) int {
+	for _, t := range terms {
+		if len(t) > 0 && runesHasPrefix(lower, at, t) {
+			return len(t)
+		}
+	}
+	return 0
+}
+
+// highlightSnippet memotong teks di sekitar kecocokan pertama dan membungkus
+// setiap kecocokan dengan <mark>. Teks di-escape agar aman dirender sebagai HTML.
+func highlightSnippet(text string, terms [][]rune) (string, bool) {
+	orig := []rune(text)
+	lower := lowerRunes(text)
+
+	first := -1
+	for i := range lower {
+		if matchAt(lower, i, terms) > 0 {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	start := first - searchSnippetLead
+	if start < 0 {
+		start = 0
+	}
+	end := start + searchSnippetRunes
+	if end > len(orig) {
+		end = len(orig)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	plain := start
+	for i := start; i < end; {
+		n := matchAt(lower, i, terms)
+		if n == 0 {
+			i++
+			continue
+		}
+		if i+n > end {
+			n = end - i
+		}
+		b.WriteString(html.EscapeString(string(orig[plain:i])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(orig[i : i+n])))
+		b.WriteString("</mark>")
+		i += n
+		plain = i
+	}
+	b.WriteString(html.EscapeString(string(orig[plain:end])))
+	if end < len(orig) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+func buildHighlights(ach model.Achievement, terms []string) []model.SearchHighlight {
+	lowered := make([][]rune, len(terms))
+	for i, t := range terms {
+		lowered[i] = lowerRunes(t)
+	}
+
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"title", ach.Title},
+		{"description", ach.Description},
+		{"tags", strings.Join(ach.Tags, ", ")},
+		{"details.competitionName", ach.Details.CompetitionName},
+		{"details.publicationTitle", ach.Details.PublicationTitle},
+		{"details.organizationName", ach.Details.OrganizationName},
+		{"details.certificationName", ach.Details.CertificationName},
+	}
+
+	out := []model.SearchHighlight{}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if snippet, ok := highlightSnippet(f.value, lowered); ok {
+			out = append(out, model.SearchHighlight{Field: f.name, Snippet: snippet})
+		}
+	}
+	return out
+}
+
+func (s *AchievementService) SearchAchievementsService(c *fiber.Ctx) error {
+	role := getUserRole(c)
+	userID := getUserID(c)
+
+	var fieldErrs []model.FieldError
+	q := strings.TrimSpace(c.Query("q"))
+	if utf8.RuneCountInString(q) < searchMinQueryLength {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "q", Message: "minimal 2 karakter"})
+	}
+	statuses := parseStatusQuery(c, role, &fieldErrs)
+	page := parsePositiveQuery(c, "page", 1, &fieldErrs)
+	limit := parsePositiveQuery(c, "limit", defaultListLimit, &fieldErrs)
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: fieldErrs})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hydrateTimeout)
+	defer cancel()
+
+	hits, err := s.Mongo.SearchText(ctx, q)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal melakukan pencarian"})
+	}
+
+	results := []model.AchievementSearchResult{}
+	if len(hits) > 0 {
+		scoreByID := make(map[string]float64, len(hits))
+		ids := make([]string, 0, len(hits))
+		for _, h := range hits {
+			scoreByID[h.MongoID] = h.Score
+			ids = append(ids, h.MongoID)
+		}
+
+		// Status dan hak akses tetap ditentukan oleh PostgreSQL. Satu dokumen tim
+		// bisa punya banyak reference, jadi seluruh baris yang cocok diambil
+		// sekaligus sebelum diurutkan dan dipotong per halaman.
+		filter := model.AchievementListFilter{
+			Statuses:           statuses,
+			MongoIDs:           ids,
+			RestrictToMongoIDs: true,
+			Page:               1,
+			Limit:              math.MaxInt32,
+		}
+		scopeListFilter(&filter, role, userID)
+
+		refs, _, err := s.PGRepo.ListReferences(filter)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal melakukan pencarian"})
+		}
+
+		for _, ref := range refs {
+			results = append(results, model.AchievementSearchResult{
+				AchievementDetailResponse: ref,
+				Score:                     scoreByID[ref.MongoID],
+			})
+		}
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+	}
+
+	total := len(results)
+	from := (page - 1) * limit
+	if from > total {
+		from = total
+	}
+	to := from + limit
+	if to > total {
+		to = total
+	}
+	pageItems := results[from:to]
+
+	pageIDs := make([]string, 0, len(pageItems))
+	for _, item := range pageItems {
+		pageIDs = append(pageIDs, item.MongoID)
+	}
+	docs, err := s.Mongo.FindByHexIDs(ctx, pageIDs, nil)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal melakukan pencarian"})
+	}
+
+	terms := searchTerms(q)
+	now := time.Now()
+	for i := range pageItems {
+		pageItems[i].Achievement = docs[pageItems[i].MongoID].ForReference(pageItems[i].ReferenceID)
+		pageItems[i].Highlights = buildHighlights(pageItems[i].Achievement, terms)
+		applySLA(&pageItems[i].AchievementDetailResponse, now)
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   pageItems,
+		Meta:   model.NewPageMeta(page, limit, total),
+	})
+}
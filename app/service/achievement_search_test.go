@@ -0,0 +1,105 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"robot", []string{"robot"}},
+		{`robot "juara umum" -gagal`, []string{"juara umum", "robot"}},
+		{`"" lomba`, []string{"lomba"}},
+		{"-semua -negasi", nil},
+	}
+
+	for _, tt := range tests {
+		if got := searchTerms(tt.q); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchTerms(%q) = %q, want %q", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+		ok    bool
+	}{
+		{
+			name:  "no match",
+			text:  "Juara lomba debat",
+			terms: []string{"robot"},
+			want:  "",
+			ok:    false,
+		},
+		{
+			name:  "case insensitive",
+			text:  "Juara 1 Lomba Robot",
+			terms: []string{"robot"},
+			want:  "Juara 1 Lomba <mark>Robot</mark>",
+			ok:    true,
+		},
+		{
+			name:  "multibyte case mapping",
+			text:  "Olimpiade ÉCOLE nasional",
+			terms: []string{"école"},
+			want:  "Olimpiade <mark>ÉCOLE</mark> nasional",
+			ok:    true,
+		},
+		{
+			name:  "html escaped",
+			text:  "<b>robot</b> & drone",
+			terms: []string{"robot"},
+			want:  "&lt;b&gt;<mark>robot</mark>&lt;/b&gt; &amp; drone",
+			ok:    true,
+		},
+		{
+			name:  "longer term preferred",
+			text:  "juara umum dan juara",
+			terms: []string{"juara umum", "juara"},
+			want:  "<mark>juara umum</mark> dan <mark>juara</mark>",
+			ok:    true,
+		},
+		{
+			name:  "truncated on both sides",
+			text:  strings.Repeat("a", 100) + "robot" + strings.Repeat("b", 100),
+			terms: []string{"robot"},
+			want:  "…" + strings.Repeat("a", 40) + "<mark>robot</mark>" + strings.Repeat("b", 75) + "…",
+			ok:    true,
+		},
+		{
+			name:  "truncated on rune boundaries",
+			text:  strings.Repeat("é", 100) + "robot" + strings.Repeat("ü", 100),
+			terms: []string{"robot"},
+			want:  "…" + strings.Repeat("é", 40) + "<mark>robot</mark>" + strings.Repeat("ü", 75) + "…",
+			ok:    true,
+		},
+		{
+			name:  "match cut at snippet end",
+			text:  "robot " + strings.Repeat("x", 113) + "robotika",
+			terms: []string{"robot"},
+			want:  "<mark>robot</mark> " + strings.Repeat("x", 113) + "<mark>r</mark>…",
+			ok:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := make([][]rune, len(tt.terms))
+			for i, term := range tt.terms {
+				terms[i] = lowerRunes(term)
+			}
+			got, ok := highlightSnippet(tt.text, terms)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("highlightSnippet = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
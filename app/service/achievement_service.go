@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"time"
 
@@ -12,22 +14,35 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var errStatusChanged = errors.New("status reference sudah berubah")
+
 type AchievementService struct {
-	PGRepo  *repository.AchievementRefRepo
-	Mongo   *repository.AchievementMongoRepo
-	PG      *sql.DB
-	MongoDB *mongo.Database
+	PGRepo    *repository.AchievementRefRepo
+	Mongo     *repository.AchievementMongoRepo
+	Types     *repository.AchievementTypeRepo
+	Team      *repository.TeamMemberRepo
+	Notif     *repository.NotificationRepo
+	Revisions *repository.AchievementRevisionRepo
+	Outbox    *repository.OutboxRepo
+	PG        *sql.DB
+	MongoDB   *mongo.Database
 }
 
 func NewAchievementService(pg *sql.DB, mongoDB *mongo.Database) *AchievementService {
 	return &AchievementService{
-		PGRepo:  repository.NewAchievementRefRepo(pg),
-		Mongo:   repository.NewAchievementMongoRepo(mongoDB),
-		PG:      pg,
-		MongoDB: mongoDB,
+		PGRepo:    repository.NewAchievementRefRepo(pg),
+		Mongo:     repository.NewAchievementMongoRepo(mongoDB),
+		Types:     repository.NewAchievementTypeRepo(mongoDB),
+		Team:      repository.NewTeamMemberRepo(pg),
+		Notif:     repository.NewNotificationRepo(pg),
+		Revisions: repository.NewAchievementRevisionRepo(mongoDB),
+		Outbox:    repository.NewOutboxRepo(pg),
+		PG:        pg,
+		MongoDB:   mongoDB,
 	}
 }
 
@@ -60,48 +75,128 @@ func (s *AchievementService) CreateAchievementService(c *fiber.Ctx) error {
 		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Request body tidak valid"})
 	}
 
+	ctx := context.Background()
+
+	var fieldErrs []model.FieldError
+	if req.Title == "" {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "title", Message: "wajib diisi"})
+	}
+	details, typeVersion, detailErrs, err := s.resolveDetails(ctx, req.AchievementType, req.Details)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memeriksa tipe prestasi"})
+	}
+	fieldErrs = append(fieldErrs, detailErrs...)
+
+	renewalOf := ""
+	if req.RenewalOf != "" {
+		var renewalErrs []model.FieldError
+		renewalOf, renewalErrs = s.resolveRenewal(ctx, studentID, req.AchievementType, req.RenewalOf)
+		fieldErrs = append(fieldErrs, renewalErrs...)
+	}
+
+	teamVerification := ""
+	members, memberErrs := s.validateTeamMembers(studentID, req.TeamMemberIDs)
+	fieldErrs = append(fieldErrs, memberErrs...)
+	if len(req.TeamMemberIDs) > 0 {
+		teamVerification = req.TeamVerification
+		if teamVerification == "" {
+			teamVerification = model.TeamVerificationPerMember
+		}
+		if teamVerification != model.TeamVerificationPerMember && teamVerification != model.TeamVerificationOwnerAdvisor {
+			fieldErrs = append(fieldErrs, model.FieldError{Field: "team_verification", Message: "harus per_member atau owner_advisor"})
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Validasi gagal", Data: fieldErrs})
+	}
+
 	now := time.Now()
 
 	ach := model.Achievement{
-		StudentID:       studentID,
-		AchievementType: req.AchievementType,
-		Title:           req.Title,
-		Description:     req.Description,
-		Tags:            req.Tags,
-		Points:          0,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:               primitive.NewObjectID(),
+		StudentID:        studentID,
+		AchievementType:  req.AchievementType,
+		TypeVersion:      typeVersion,
+		Title:            req.Title,
+		Description:      req.Description,
+		Details:          details,
+		Tags:             req.Tags,
+		Points:           0,
+		IsTeam:           len(req.TeamMemberIDs) > 0,
+		TeamVerification: teamVerification,
+		RenewalOf:        renewalOf,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	// Dokumen MongoDB dibuat lewat outbox: reference dan event disimpan dalam
+	// satu transaksi, lalu dokumen dibuat inline atau oleh worker.
+	mongoHex := ach.ID.Hex()
+	doc, err := bson.MarshalExtJSON(ach, true, false)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menyiapkan dokumen MongoDB"})
 	}
 
-	if req.Details != nil {
-		ach.Details.CustomFields = req.Details
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuat reference"})
 	}
 
-	ctx := context.Background()
-	mongoHex, err := s.Mongo.Create(ctx, ach)
+	refID, err := s.PGRepo.CreateReference(tx, studentID, mongoHex)
 	if err != nil {
-		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuat dokumen MongoDB"})
+		tx.Rollback()
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuat reference"})
 	}
 
-	refID, err := s.PGRepo.CreateReference(studentID, mongoHex)
+	payload := model.CreateAchievementPayload{ReferenceID: refID, MongoID: mongoHex, Document: doc}
+	eventID, err := s.Outbox.Enqueue(tx, model.OutboxAchievementCreate, refID, payload, outboxInlineDelaySeconds)
 	if err != nil {
-		_ = s.Mongo.DeleteByHexID(ctx, mongoHex)
+		tx.Rollback()
 		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuat reference"})
 	}
 
-	return c.JSON(model.APIResponse{
-		Status: "success",
-		Data: fiber.Map{
-			"reference_id": refID,
-			"mongo_id":     mongoHex,
-		},
-	})
+	for _, memberID := range members {
+		if err := s.Team.Invite(tx, mongoHex, memberID, studentID); err != nil {
+			tx.Rollback()
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengundang anggota tim"})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuat reference"})
+	}
+
+	data := fiber.Map{
+		"reference_id":  refID,
+		"mongo_id":      mongoHex,
+		"invited_count": len(members),
+		"synced":        true,
+	}
+	if err := s.runOutboxInline(ctx, eventID, model.OutboxAchievementCreate, payload); err != nil {
+		// Dokumen akan dibuat oleh worker; sampai saat itu detail, update, dan
+		// submit menjawab 409 "sedang diproses".
+		log.Printf("Create achievement %s deferred to outbox: %v", refID, err)
+		data["synced"] = false
+		return c.Status(202).JSON(model.APIResponse{
+			Status:  "success",
+			Message: "Prestasi sedang diproses",
+			Data:    data,
+		})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: data})
 }
 
 func (s *AchievementService) UpdateAchievementService(c *fiber.Ctx) error {
 	userID := getUserID(c)
 	refID := c.Params("id")
 
+	expected, rerr := requireIfMatch(c)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
 	ref, err := s.PGRepo.GetReference(refID)
 	if err != nil || ref.StudentID != userID {
 		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Reference tidak ditemukan"})
@@ -111,13 +206,30 @@ func (s *AchievementService) UpdateAchievementService(c *fiber.Ctx) error {
 		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Hanya draft/rejected yang bisa update"})
 	}
 
+	current, rerr := s.findDocument(ref.MongoID)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+	if current.StudentID != userID {
+		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: "Hanya pemilik prestasi tim yang dapat mengubah data"})
+	}
+	if rerr := s.ensureDocumentUnlocked(ref.MongoID); rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
 	var req model.UpdateAchievementRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Invalid body"})
 	}
+	if req.Points != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Validasi gagal", Data: []model.FieldError{{Field: "points", Message: "hanya ditentukan oleh dosen wali saat verifikasi"}}})
+	}
 
 	update := bson.M{}
 	if req.Title != nil {
+		if *req.Title == "" {
+			return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Validasi gagal", Data: []model.FieldError{{Field: "title", Message: "wajib diisi"}}})
+		}
 		update["title"] = *req.Title
 	}
 	if req.Description != nil {
@@ -127,20 +239,67 @@ func (s *AchievementService) UpdateAchievementService(c *fiber.Ctx) error {
 		update["tags"] = req.Tags
 	}
 	if req.Details != nil {
-		update["details"] = req.Details
-	}
-	if req.Points != nil {
-		update["points"] = *req.Points
+		details, typeVersion, fieldErrs, err := s.resolveDetails(context.Background(), current.AchievementType, req.Details)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memeriksa tipe prestasi"})
+		}
+		if len(fieldErrs) > 0 {
+			return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Validasi gagal", Data: fieldErrs})
+		}
+		update["details"] = details
+		update["typeVersion"] = typeVersion
+		if current.ExpiredAt != nil && details.ValidUntil != nil && details.ValidUntil.After(time.Now()) {
+			update["expiredAt"] = nil
+			update["expiryWarnedAt"] = nil
+			update["pointsExcluded"] = false
+		}
 	}
 
 	if len(update) == 0 {
 		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Tidak ada perubahan"})
 	}
 
-	err = s.Mongo.UpdateByHexID(context.Background(), ref.MongoID, update)
+	// Reference dikunci selama dokumen diubah agar submit/verifikasi tidak bisa
+	// berjalan di antaranya; dokumen hanya diubah jika versinya masih cocok.
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal update"})
+	}
+	defer tx.Rollback()
+
+	status, refVersion, err := s.PGRepo.LockReference(tx, refID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal update"})
+	}
+	if status != "draft" && status != "rejected" {
+		return c.Status(409).JSON(model.APIResponse{Status: "error", Error: "Status prestasi sudah berubah, muat ulang data"})
+	}
+	if !expected.matchesRef(refVersion) {
+		return c.Status(412).JSON(model.APIResponse{Status: "error", Error: versionMismatchMessage})
+	}
+
+	docVersion := current.Version
+	if expected != nil {
+		docVersion = expected.Doc
+	}
+	ok, err := s.Mongo.UpdateIfVersion(context.Background(), ref.MongoID, docVersion, update)
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal update MongoDB"})
 	}
+	if !ok {
+		return c.Status(412).JSON(model.APIResponse{Status: "error", Error: versionMismatchMessage})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal update"})
+	}
+	c.Set(fiber.HeaderETag, achievementETag(refVersion, docVersion+1))
+
+	if revisionOnEdit() {
+		if err := s.snapshotRevision(context.Background(), refID, ref.MongoID, model.RevisionReasonEdit, userID); err != nil {
+			log.Printf("Failed to save revision for %s: %v", refID, err)
+		}
+	}
 
 	return c.JSON(model.APIResponse{Status: "success", Message: "Updated"})
 }
@@ -158,7 +317,12 @@ func (s *AchievementService) DeleteAchievementService(c *fiber.Ctx) error {
 		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Hanya draft yang boleh dihapus"})
 	}
 
-	err = s.PGRepo.SoftDeleteReference(refID)
+	err = s.transitionReference(refID, []string{"draft"}, nil, func(tx *sql.Tx, from string) error {
+		return s.PGRepo.SoftDeleteReference(tx, refID, from, userID)
+	})
+	if err == errStatusChanged {
+		return c.Status(409).JSON(model.APIResponse{Status: "error", Error: "Status prestasi sudah berubah, muat ulang data"})
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menghapus"})
 	}
@@ -170,6 +334,11 @@ func (s *AchievementService) SubmitAchievementService(c *fiber.Ctx) error {
 	userID := getUserID(c)
 	refID := c.Params("id")
 
+	expected, rerr := requireIfMatch(c)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
 	ref, err := s.PGRepo.GetReference(refID)
 	if err != nil || ref.StudentID != userID {
 		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Reference tidak ditemukan"})
@@ -179,7 +348,40 @@ func (s *AchievementService) SubmitAchievementService(c *fiber.Ctx) error {
 		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Tidak bisa disubmit"})
 	}
 
-	err = s.PGRepo.SubmitReference(refID)
+	ach, rerr := s.findDocument(ref.MongoID)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	rule, err := s.activeEvidenceRule(context.Background(), ach.AchievementType)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memeriksa tipe prestasi"})
+	}
+	if fieldErrs := checkEvidence(rule, ach.Attachments); len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Bukti pendukung belum memenuhi syarat", Data: fieldErrs})
+	}
+
+	err = s.transitionReference(refID, []string{"draft", "rejected"}, expected, func(tx *sql.Tx, from string) error {
+		// Dibaca ulang setelah reference dikunci: edit dari tab lain menaikkan versi dokumen.
+		latest, err := s.Mongo.FindByHexID(context.Background(), ref.MongoID)
+		if err != nil {
+			return err
+		}
+		if !expected.matchesDoc(latest.Version) {
+			return errVersionMismatch
+		}
+		if err := s.PGRepo.SubmitReference(tx, refID, from, userID); err != nil {
+			return err
+		}
+		// Snapshot disimpan sebelum commit agar setiap submit selalu punya revisi.
+		return s.snapshotRevision(context.Background(), refID, ref.MongoID, model.RevisionReasonSubmit, userID)
+	})
+	if err == errStatusChanged {
+		return c.Status(409).JSON(model.APIResponse{Status: "error", Error: "Status prestasi sudah berubah, muat ulang data"})
+	}
+	if err == errVersionMismatch {
+		return c.Status(412).JSON(model.APIResponse{Status: "error", Error: versionMismatchMessage})
+	}
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal submit"})
 	}
@@ -187,101 +389,154 @@ func (s *AchievementService) SubmitAchievementService(c *fiber.Ctx) error {
 	return c.JSON(model.APIResponse{Status: "success", Message: "submitted"})
 }
 
-func (s *AchievementService) VerifyAchievementService(c *fiber.Ctx) error {
-    verifierID := getUserID(c)
-    role := getUserRole(c)
-    refID := c.Params("id")
+func (s *AchievementService) WithdrawAchievementService(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	refID := c.Params("id")
 
-    if role != "Dosen Wali" {
-        return c.Status(403).JSON(model.APIResponse{
-            Status: "error",
-            Error:  "Akses ditolak",
-        })
-    }
+	ref, err := s.PGRepo.GetReferenceDetail(refID)
+	if err != nil || ref.StudentID != userID {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Reference tidak ditemukan"})
+	}
 
-    ref, err := s.PGRepo.GetReferenceWithAdvisor(refID, verifierID)
-    if err != nil || ref.AdvisorID != verifierID {
-        return c.Status(403).JSON(model.APIResponse{
-            Status: "error",
-            Error:  "Anda bukan dosen wali mahasiswa ini",
-        })
-    }
+	if ref.ReferenceStatus != "submitted" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Hanya prestasi berstatus submitted yang bisa ditarik"})
+	}
 
-    if ref.ReferenceStatus != "submitted" {
-        return c.Status(400).JSON(model.APIResponse{
-            Status: "error",
-            Error:  "Prestasi hanya bisa diverifikasi setelah disubmit",
-        })
-    }
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menarik prestasi"})
+	}
 
-    var req struct {
-        Points int `json:"points"`
-    }
+	ok, err := s.PGRepo.WithdrawReference(tx, refID, userID)
+	if err != nil {
+		tx.Rollback()
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menarik prestasi"})
+	}
+	if !ok {
+		tx.Rollback()
+		return c.Status(409).JSON(model.APIResponse{Status: "error", Error: "Prestasi sudah mulai direview oleh dosen wali"})
+	}
 
-    if err := c.BodyParser(&req); err != nil {
-        return c.Status(400).JSON(model.APIResponse{
-            Status: "error",
-            Error:  "Body request tidak valid",
-        })
-    }
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menarik prestasi"})
+	}
 
-    if req.Points <= 0 {
-        return c.Status(400).JSON(model.APIResponse{
-            Status: "error",
-            Error:  "Points harus lebih dari 0",
-        })
-    }
+	notify(s.Notif, ref.AdvisorID, model.NotificationAchievementWithdrawn,
+		"Prestasi ditarik dari antrean verifikasi",
+		"Mahasiswa menarik kembali prestasi yang sebelumnya disubmit untuk diperbaiki.",
+		&refID)
 
-    err = s.Mongo.UpdateByHexID(context.Background(), ref.MongoID, bson.M{
-        "points":   req.Points,
-        "updatedAt": time.Now(),
-    })
-    if err != nil {
-        return c.Status(500).JSON(model.APIResponse{
-            Status: "error",
-            Error:  "Gagal update points di MongoDB",
-        })
-    }
+	return c.JSON(model.APIResponse{Status: "success", Message: "withdrawn"})
+}
 
-    err = s.PGRepo.VerifyReference(refID, verifierID)
-    if err != nil {
-        return c.Status(500).JSON(model.APIResponse{
-            Status: "error",
-            Error:  "Gagal verifikasi",
-        })
-    }
+// transitionReference mengunci reference, memastikan statusnya masih salah satu
+// dari allowed dan versinya cocok dengan expected (nil = tanpa pemeriksaan
+// versi), lalu menjalankan apply dalam transaksi yang sama.
+func (s *AchievementService) transitionReference(refID string, allowed []string, expected *entityVersion, apply func(tx *sql.Tx, from string) error) error {
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return err
+	}
 
-    return c.JSON(model.APIResponse{
-        Status:  "success",
-        Message: "Verified & points updated",
-        Data: fiber.Map{
-            "points": req.Points,
-        },
-    })
+	status, version, err := s.PGRepo.LockReference(tx, refID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if !contains(allowed, status) {
+		tx.Rollback()
+		return errStatusChanged
+	}
+	if !expected.matchesRef(version) {
+		tx.Rollback()
+		return errVersionMismatch
+	}
+
+	if err := apply(tx, status); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (s *AchievementService) RejectAchievementService(c *fiber.Ctx) error {
-	advisorID := getUserID(c)
-	refID := c.Params("id")
+// ensureDocumentUnlocked menolak perubahan pada dokumen yang sudah diverifikasi,
+// termasuk dokumen tim yang salah satu reference anggotanya sudah verified.
+func (s *AchievementService) ensureDocumentUnlocked(mongoHex string) *reviewError {
+	locked, err := s.PGRepo.IsDocumentLocked(mongoHex)
+	if err != nil {
+		return &reviewError{Status: 500, Message: "Gagal memeriksa status prestasi"}
+	}
+	if locked {
+		return &reviewError{Status: 409, Message: "Prestasi sudah diverifikasi dan tidak bisa diubah"}
+	}
+	return nil
+}
 
-	ref, err := s.PGRepo.GetReferenceWithAdvisor(refID, advisorID)
-	if err != nil || ref.AdvisorID != advisorID {
-		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: "Anda bukan dosen wali mahasiswa ini"})
+func (s *AchievementService) VerifyAchievementService(c *fiber.Ctx) error {
+	var req struct {
+		Points int `json:"points"`
 	}
 
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Body request tidak valid",
+		})
+	}
+
+	expected, rerr := requireIfMatch(c)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	teamVerified, rerr := s.verifyOne(getUserID(c), getUserRole(c), c.Params("id"), req.Points, expected)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  rerr.Message,
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "Verified & points updated",
+		Data: fiber.Map{
+			"points":                   req.Points,
+			"team_references_verified": teamVerified,
+		},
+	})
+}
+
+func (s *AchievementService) RejectAchievementService(c *fiber.Ctx) error {
 	var body struct {
 		Note string `json:"note"`
 	}
 	_ = c.BodyParser(&body)
 
-	err = s.PGRepo.RejectReference(refID, advisorID, body.Note)
-	if err != nil {
-		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal reject"})
+	expected, rerr := requireIfMatch(c)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	if rerr := s.rejectOne(getUserID(c), getUserRole(c), c.Params("id"), body.Note, expected); rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
 	}
 
 	return c.JSON(model.APIResponse{Status: "success", Message: "rejected"})
 }
 
+// cascadeTeamReview menentukan apakah hasil review ikut diterapkan ke reference
+// anggota tim lain: prestasi tim memakai mode owner_advisor dan ref adalah milik
+// pemilik prestasi.
+func (s *AchievementService) cascadeTeamReview(ref *model.AchievementDetailResponse) (bool, error) {
+	ach, err := s.Mongo.FindByHexID(context.Background(), ref.MongoID)
+	if err != nil {
+		return false, err
+	}
+	return ach.IsTeam && ach.TeamVerification == model.TeamVerificationOwnerAdvisor && ach.StudentID == ref.StudentID, nil
+}
+
 func (s *AchievementService) GetAchievementDetailService(c *fiber.Ctx) error {
 	refID := c.Params("id")
 	role := getUserRole(c)
@@ -316,15 +571,17 @@ func (s *AchievementService) GetAchievementDetailService(c *fiber.Ctx) error {
 		})
 	}
 
-	ach, err := s.Mongo.FindByHexID(context.Background(), ref.MongoID)
-	if err != nil {
-		return c.Status(500).JSON(model.APIResponse{
+	ach, rerr := s.findDocument(ref.MongoID)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "Gagal mengambil data MongoDB",
+			Error:  rerr.Message,
 		})
 	}
 
-	ref.Achievement = *ach
+	ref.Achievement = ach.ForReference(ref.ReferenceID)
+	applySLA(ref, time.Now())
+	c.Set(fiber.HeaderETag, achievementETag(ref.Version, ach.Version))
 
 	return c.JSON(model.APIResponse{
 		Status: "success",
@@ -336,33 +593,48 @@ func (s *AchievementService) ListAchievementsService(c *fiber.Ctx) error {
 	role := getUserRole(c)
 	userID := getUserID(c)
 
-	var list []model.AchievementDetailResponse
-	var err error
-
-	switch role {
+	filter, docFilter, fieldErrs := parseAchievementListQuery(c, role)
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: fieldErrs})
+	}
 
-	case "Admin":
-		list, err = s.PGRepo.ListForAdmin()
+	scopeListFilter(&filter, role, userID)
 
-	case "Dosen Wali":
-		list, err = s.PGRepo.ListForAdvisor(userID)
+	ctx := context.Background()
 
-	default:
-		list, err = s.PGRepo.ListForStudent(userID)
+	// Filter yang hanya ada di dokumen MongoDB dijalankan lebih dulu untuk membatasi reference.
+	if !docFilter.IsEmpty() {
+		ids, err := s.Mongo.FindIDs(ctx, docFilter)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data"})
+		}
+		filter.MongoIDs = ids
+		filter.RestrictToMongoIDs = true
 	}
 
+	list, total, err := s.PGRepo.ListReferences(filter)
 	if err != nil {
 		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data"})
 	}
 
+	missing, err := hydrateReferences(ctx, s.Mongo, list)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data prestasi"})
+	}
+
+	now := time.Now()
 	for i := range list {
-		doc, err := s.Mongo.FindByHexID(context.Background(), list[i].MongoID)
-		if err == nil {
-			list[i].Achievement = *doc
-		}
+		applySLA(&list[i], now)
 	}
 
-	return c.JSON(model.APIResponse{Status: "success", Data: list})
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   list,
+		Meta: model.AchievementListMeta{
+			PageMeta:         model.NewPageMeta(filter.Page, filter.Limit, total),
+			MissingDocuments: missing,
+		},
+	})
 }
 
 func (s *AchievementService) GetHistoryService(c *fiber.Ctx) error {
@@ -378,7 +650,7 @@ func (s *AchievementService) GetHistoryService(c *fiber.Ctx) error {
         })
     }
 
-    if role == "Student" && ref.StudentID != userID {
+    if role == "Mahasiswa" && ref.StudentID != userID {
         return c.Status(403).JSON(model.APIResponse{
             Status: "error",
             Error:  "Tidak boleh melihat history milik orang lain",
@@ -395,6 +667,39 @@ func (s *AchievementService) GetHistoryService(c *fiber.Ctx) error {
         }
     }
 
+    history, err := s.PGRepo.ListStatusHistory(refID)
+    if err != nil {
+        return c.Status(500).JSON(model.APIResponse{
+            Status: "error",
+            Error:  "Gagal mengambil riwayat status",
+        })
+    }
+
+    // Reference lama belum punya riwayat tercatat, jadi timeline disusun dari kolom status.
+    var timeline interface{} = history
+    if len(history) == 0 {
+        timeline = legacyTimeline(ref)
+    }
+
+    return c.JSON(model.APIResponse{
+        Status: "success",
+        Data: fiber.Map{
+            "reference_id":  ref.ReferenceID,
+            "mongo_id":      ref.MongoID,
+            "student_id":    ref.StudentID,
+            "status":        ref.ReferenceStatus,
+            "timeline":      timeline,
+            "created_at":    ref.CreatedAtRef,
+            "submitted_at":  ref.SubmittedAt,
+            "verified_at":   ref.VerifiedAt,
+            "verified_by":   ref.VerifiedBy,
+            "rejection_note": ref.RejectionNote,
+            "updated_at":    ref.UpdatedAtRef,
+        },
+    })
+}
+
+func legacyTimeline(ref *model.AchievementDetailResponse) []fiber.Map {
     timeline := []fiber.Map{}
 
     timeline = append(timeline, fiber.Map{
@@ -440,22 +745,7 @@ func (s *AchievementService) GetHistoryService(c *fiber.Ctx) error {
         })
     }
 
-    return c.JSON(model.APIResponse{
-        Status: "success",
-        Data: fiber.Map{
-            "reference_id":  ref.ReferenceID,
-            "mongo_id":      ref.MongoID,
-            "student_id":    ref.StudentID,
-            "status":        ref.ReferenceStatus,
-            "timeline":      timeline,
-            "created_at":    ref.CreatedAtRef,
-            "submitted_at":  ref.SubmittedAt,
-            "verified_at":   ref.VerifiedAt,
-            "verified_by":   ref.VerifiedBy,
-            "rejection_note": ref.RejectionNote,
-            "updated_at":    ref.UpdatedAtRef,
-        },
-    })
+    return timeline
 }
 
 func (s *AchievementService) UploadAttachmentsService(c *fiber.Ctx) error {
@@ -470,6 +760,19 @@ func (s *AchievementService) UploadAttachmentsService(c *fiber.Ctx) error {
 		})
 	}
 
+	if ref.ReferenceStatus != "draft" && ref.ReferenceStatus != "rejected" {
+		return c.Status(400).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Lampiran hanya bisa ditambahkan pada draft/rejected",
+		})
+	}
+	if rerr := s.ensureDocumentUnlocked(ref.MongoID); rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  rerr.Message,
+		})
+	}
+
 	form, err := c.MultipartForm()
 	if err != nil {
 		return c.Status(400).JSON(model.APIResponse{
@@ -486,6 +789,36 @@ func (s *AchievementService) UploadAttachmentsService(c *fiber.Ctx) error {
 		})
 	}
 
+	ach, rerr := s.findDocument(ref.MongoID)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{
+			Status: "error",
+			Error:  rerr.Message,
+		})
+	}
+	if ach.StudentID != userID {
+		return c.Status(403).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Hanya pemilik prestasi tim yang dapat menambah lampiran",
+		})
+	}
+
+	rule, err := s.activeEvidenceRule(context.Background(), ach.AchievementType)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Gagal memeriksa tipe prestasi",
+		})
+	}
+	for _, file := range files {
+		if !evidenceAllowsFile(rule, file.Filename, file.Header.Get("Content-Type")) {
+			return c.Status(400).JSON(model.APIResponse{
+				Status: "error",
+				Error:  "Tipe file " + file.Filename + " tidak diizinkan untuk tipe prestasi ini",
+			})
+		}
+	}
+
 	saveDir := "uploads/achievements/" + refID
 	_ = os.MkdirAll(saveDir, os.ModePerm)
 
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const slaBatchLimit = 200
+
+// slaReviewHours adalah batas waktu verifikasi sejak prestasi disubmit.
+func slaReviewHours() int {
+	return utils.GetEnvInt("ACHIEVEMENT_SLA_REVIEW_HOURS", 168)
+}
+
+// slaReminderHours adalah waktu tunggu sebelum dosen wali diingatkan, selalu
+// lebih kecil dari batas SLA.
+func slaReminderHours() int {
+	review := slaReviewHours()
+	reminder := utils.GetEnvInt("ACHIEVEMENT_SLA_REMINDER_HOURS", 120)
+	if reminder <= 0 || reminder >= review {
+		return review / 2
+	}
+	return reminder
+}
+
+// applySLA mengisi status SLA untuk prestasi yang sedang menunggu verifikasi.
+func applySLA(ref *model.AchievementDetailResponse, now time.Time) {
+	if ref.ReferenceStatus != "submitted" || ref.SubmittedAt == nil {
+		return
+	}
+
+	dueAt := ref.SubmittedAt.Add(time.Duration(slaReviewHours()) * time.Hour)
+	sla := &model.SLAStatus{
+		DueAt:       dueAt,
+		HoursLeft:   int(dueAt.Sub(now).Hours()),
+		RemindedAt:  ref.SLARemindedAt,
+		EscalatedAt: ref.EscalatedAt,
+	}
+
+	switch {
+	case ref.EscalatedAt != nil:
+		sla.State = model.SLAStateEscalated
+	case now.After(dueAt):
+		sla.State = model.SLAStateOverdue
+	case !now.Before(ref.SubmittedAt.Add(time.Duration(slaReminderHours()) * time.Hour)):
+		sla.State = model.SLAStateDueSoon
+	default:
+		sla.State = model.SLAStateOnTrack
+	}
+	ref.SLA = sla
+}
+
+// CheckSLA mengirim pengingat ke dosen wali untuk submission yang mendekati
+// batas SLA, lalu mengeskalasi submission yang melewati batas ke antrean admin.
+func (s *AchievementService) CheckSLA(ctx context.Context) (reminded, escalated int, err error) {
+	reminders, err := s.PGRepo.ListSLAReminderDue(slaReminderHours(), slaBatchLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, c := range reminders {
+		if ctx.Err() != nil {
+			return reminded, escalated, ctx.Err()
+		}
+		if err := s.PGRepo.MarkSLAReminded(c.ReferenceID); err != nil {
+			log.Printf("SLA reminder %s failed: %v", c.ReferenceID, err)
+			continue
+		}
+		refID := c.ReferenceID
+		notify(s.Notif, c.AdvisorID, model.NotificationSLAReminder,
+			"Prestasi menunggu verifikasi",
+			fmt.Sprintf("Prestasi %s sudah menunggu sejak %s dan mendekati batas waktu verifikasi.",
+				c.StudentName, c.SubmittedAt.Format("02-01-2006 15:04")),
+			&refID)
+		reminded++
+	}
+
+	overdue, err := s.PGRepo.ListSLAEscalationDue(slaReviewHours(), slaBatchLimit)
+	if err != nil {
+		return reminded, 0, err
+	}
+	for _, c := range overdue {
+		if ctx.Err() != nil {
+			return reminded, escalated, ctx.Err()
+		}
+		ok, err := s.PGRepo.MarkEscalated(c.ReferenceID)
+		if err != nil {
+			log.Printf("SLA escalation %s failed: %v", c.ReferenceID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		refID := c.ReferenceID
+		reason := "melewati batas waktu verifikasi"
+		if c.AdvisorID == "" {
+			reason = "mahasiswa tidak memiliki dosen wali"
+		}
+		message := fmt.Sprintf("Prestasi %s (%s) dieskalasi ke antrean admin karena %s.", c.StudentName, c.StudyProgram, reason)
+		s.notifyEscalation(c.StudyProgram, message, &refID)
+		notify(s.Notif, c.AdvisorID, model.NotificationSLAEscalated, "Prestasi dieskalasi", message, &refID)
+		escalated++
+	}
+
+	return reminded, escalated, nil
+}
+
+// notifyEscalation mengirim eskalasi ke admin program studi mahasiswa. Program
+// studi yang belum punya admin dikirim ke semua admin agar tidak terlewat.
+func (s *AchievementService) notifyEscalation(studyProgram, message string, refID *string) {
+	n, err := s.Notif.CreateForStudyProgramAdmins(studyProgram, model.NotificationSLAEscalated, "Prestasi dieskalasi", message, refID)
+	if err != nil {
+		log.Printf("Failed to notify study program admins for %s: %v", *refID, err)
+	}
+	if err == nil && n > 0 {
+		return
+	}
+	if err := s.Notif.CreateForRole("Admin", model.NotificationSLAEscalated, "Prestasi dieskalasi", message, refID); err != nil {
+		log.Printf("Failed to notify admins for %s: %v", *refID, err)
+	}
+}
+
+// EscalationQueueService menampilkan antrean eskalasi untuk program studi yang
+// ditangani admin yang login.
+func (s *AchievementService) EscalationQueueService(c *fiber.Ctx) error {
+	list, err := s.PGRepo.ListEscalated(getUserID(c), c.Query("study_program"))
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil antrean eskalasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: list})
+}
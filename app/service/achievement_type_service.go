@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var typeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+type AchievementTypeService struct {
+	Repo *repository.AchievementTypeRepo
+}
+
+func NewAchievementTypeService(mongoDB *mongo.Database) *AchievementTypeService {
+	return &AchievementTypeService{
+		Repo: repository.NewAchievementTypeRepo(mongoDB),
+	}
+}
+
+func (s *AchievementTypeService) ListTypesService(c *fiber.Ctx) error {
+	list, err := s.Repo.ListActive(context.Background())
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil tipe prestasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: list})
+}
+
+func (s *AchievementTypeService) GetTypeService(c *fiber.Ctx) error {
+	code := c.Params("code")
+	ctx := context.Background()
+
+	var def *model.AchievementTypeDefinition
+	var err error
+
+	if v := c.Query("version"); v != "" {
+		version, convErr := strconv.Atoi(v)
+		if convErr != nil {
+			return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "version tidak valid"})
+		}
+		def, err = s.Repo.FindVersion(ctx, code, version)
+	} else {
+		def, err = s.Repo.FindLatest(ctx, code)
+	}
+
+	if err != nil || def == nil {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Tipe prestasi tidak ditemukan"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: def})
+}
+
+func (s *AchievementTypeService) ListVersionsService(c *fiber.Ctx) error {
+	list, err := s.Repo.ListVersions(context.Background(), c.Params("code"))
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil versi tipe prestasi"})
+	}
+	if len(list) == 0 {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Tipe prestasi tidak ditemukan"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: list})
+}
+
+func (s *AchievementTypeService) CreateTypeService(c *fiber.Ctx) error {
+	var req model.SaveAchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Request body tidak valid"})
+	}
+
+	existing, err := s.Repo.FindLatest(context.Background(), req.Code)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memeriksa tipe prestasi"})
+	}
+	if existing != nil {
+		return c.Status(409).JSON(model.APIResponse{Status: "error", Error: "Kode tipe prestasi sudah digunakan, gunakan PUT untuk membuat versi baru"})
+	}
+
+	return s.saveVersion(c, req)
+}
+
+func (s *AchievementTypeService) UpdateTypeService(c *fiber.Ctx) error {
+	var req model.SaveAchievementTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Request body tidak valid"})
+	}
+	req.Code = c.Params("code")
+
+	existing, err := s.Repo.FindLatest(context.Background(), req.Code)
+	if err != nil || existing == nil {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Tipe prestasi tidak ditemukan"})
+	}
+
+	return s.saveVersion(c, req)
+}
+
+func (s *AchievementTypeService) DeleteTypeService(c *fiber.Ctx) error {
+	n, err := s.Repo.Deactivate(context.Background(), c.Params("code"))
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menonaktifkan tipe prestasi"})
+	}
+	if n == 0 {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Tipe prestasi aktif tidak ditemukan"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Message: "Tipe prestasi dinonaktifkan"})
+}
+
+func (s *AchievementTypeService) saveVersion(c *fiber.Ctx, req model.SaveAchievementTypeRequest) error {
+	var fieldErrs []model.FieldError
+	if !typeCodePattern.MatchString(req.Code) {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "code", Message: "hanya huruf kecil, angka dan underscore (2-50 karakter)"})
+	}
+	if strings.TrimSpace(req.Label) == "" {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "label", Message: "wajib diisi"})
+	}
+	if req.Evidence.MinAttachments < 0 {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "evidence.minAttachments", Message: "tidak boleh negatif"})
+	}
+	if req.DetailsSchema == nil {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "details_schema", Message: "wajib diisi"})
+	} else if err := checkJSONSchema(req.DetailsSchema); err != nil {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "details_schema", Message: err.Error()})
+	}
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Validasi gagal", Data: fieldErrs})
+	}
+
+	schemaJSON, err := json.Marshal(req.DetailsSchema)
+	if err != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "details_schema tidak valid"})
+	}
+
+	def, err := s.Repo.CreateVersion(context.Background(), model.AchievementTypeDefinition{
+		Code:        req.Code,
+		Label:       strings.TrimSpace(req.Label),
+		Description: req.Description,
+		FieldLabels: req.FieldLabels,
+		SchemaJSON:  string(schemaJSON),
+		Evidence:    req.Evidence,
+		CreatedBy:   getUserID(c),
+	})
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menyimpan tipe prestasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: def})
+}
+
+// resolveDetails memvalidasi details terhadap tipe bawaan dan/atau schema
+// versi aktif yang didefinisikan admin, lalu mengembalikan versi schema yang dipakai.
+func (s *AchievementService) resolveDetails(ctx context.Context, achType string, raw map[string]interface{}) (model.AchievementDetails, int, []model.FieldError, error) {
+	var details model.AchievementDetails
+
+	def, err := s.Types.FindLatest(ctx, achType)
+	if err != nil {
+		return details, 0, nil, err
+	}
+	if def != nil && !def.IsActive {
+		// Tipe bawaan tetap bisa dipakai dengan definisi bawaannya.
+		if !isKnownAchievementType(achType) {
+			return details, 0, []model.FieldError{{Field: "achievement_type", Message: "tipe prestasi sudah tidak aktif"}}, nil
+		}
+		def = nil
+	}
+
+	var fieldErrs []model.FieldError
+	switch {
+	case isKnownAchievementType(achType):
+		details, fieldErrs = parseAchievementDetails(achType, raw)
+	case def != nil:
+		details.CustomFields = raw
+	default:
+		return details, 0, []model.FieldError{{Field: "achievement_type", Message: "tipe prestasi tidak dikenal"}}, nil
+	}
+
+	if def == nil {
+		return details, 0, fieldErrs, nil
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(def.SchemaJSON), &schema); err != nil {
+		return details, 0, nil, err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	fieldErrs = append(fieldErrs, validateJSONSchema(schema, raw, "details")...)
+
+	return details, def.Version, fieldErrs, nil
+}
+
+// activeEvidenceRule mengembalikan aturan bukti dari versi aktif tipe prestasi,
+// atau nil jika tipe tidak memiliki definisi aktif.
+func (s *AchievementService) activeEvidenceRule(ctx context.Context, achType string) (*model.EvidenceRule, error) {
+	def, err := s.Types.FindLatest(ctx, achType)
+	if err != nil || def == nil || !def.IsActive {
+		return nil, err
+	}
+	return &def.Evidence, nil
+}
+
+func evidenceAllowsFile(rule *model.EvidenceRule, fileName, contentType string) bool {
+	if rule == nil || len(rule.AllowedFileTypes) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, allowed := range rule.AllowedFileTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == ext || allowed == strings.ToLower(contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkEvidence(rule *model.EvidenceRule, atts []model.Attachment) []model.FieldError {
+	if rule == nil {
+		return nil
+	}
+	var fieldErrs []model.FieldError
+	if len(atts) < rule.MinAttachments {
+		fieldErrs = append(fieldErrs, model.FieldError{
+			Field:   "attachments",
+			Message: "minimal " + strconv.Itoa(rule.MinAttachments) + " file bukti",
+		})
+	}
+	for i, att := range atts {
+		if !evidenceAllowsFile(rule, att.FileName, att.FileType) {
+			fieldErrs = append(fieldErrs, model.FieldError{
+				Field:   "attachments[" + strconv.Itoa(i) + "]",
+				Message: "tipe file tidak diizinkan: " + strings.Join(rule.AllowedFileTypes, ", "),
+			})
+		}
+	}
+	return fieldErrs
+}
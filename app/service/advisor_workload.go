@@ -0,0 +1,115 @@
+package service
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func roundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+// finishWorkload melengkapi rasio penolakan dan membulatkan durasi.
+func finishWorkload(w *model.AdvisorWorkload) {
+	if w.DecidedCount > 0 {
+		w.RejectionRate = roundTo(float64(w.RejectedCount)/float64(w.DecidedCount), 3)
+	}
+	for _, h := range []*float64{w.AvgTurnaroundHours, w.P90TurnaroundHours} {
+		if h != nil {
+			*h = roundTo(*h, 1)
+		}
+	}
+}
+
+func parseWorkloadFilter(c *fiber.Ctx) (model.AdvisorWorkloadFilter, []model.FieldError) {
+	var errs []model.FieldError
+	f := model.AdvisorWorkloadFilter{
+		Department: strings.TrimSpace(c.Query("department")),
+		DueHours:   slaReviewHours(),
+	}
+	f.From = parseListDate(c, "from", false, &errs)
+	f.To = parseListDate(c, "to", true, &errs)
+	checkDateRange(f.From, f.To, "to", &errs)
+	return f, errs
+}
+
+// AdvisorWorkloadService menampilkan beban verifikasi seluruh dosen wali,
+// diurutkan dari yang paling banyak submission overdue.
+func (s *ReportService) AdvisorWorkloadService(c *fiber.Ctx) error {
+	filter, fieldErrs := parseWorkloadFilter(c)
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: fieldErrs})
+	}
+
+	list, err := s.Reports.ListAdvisorWorkloads(filter)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menyusun laporan beban dosen wali"})
+	}
+	for i := range list {
+		finishWorkload(&list[i])
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: model.AdvisorWorkloadReport{
+		From:        filter.From,
+		To:          filter.To,
+		SLAHours:    filter.DueHours,
+		Lecturers:   list,
+		GeneratedAt: time.Now(),
+	}})
+}
+
+// AdvisorWorkloadDetailService menampilkan beban satu dosen wali beserta
+// daftar mahasiswa bimbingan dan submission yang overdue.
+func (s *ReportService) AdvisorWorkloadDetailService(c *fiber.Ctx) error {
+	filter, fieldErrs := parseWorkloadFilter(c)
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: fieldErrs})
+	}
+	filter.LecturerID = c.Params("id")
+	filter.Department = ""
+
+	if _, err := repository.GetLecturerByID(s.PG, filter.LecturerID); err == sql.ErrNoRows {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Dosen tidak ditemukan"})
+	} else if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data dosen"})
+	}
+
+	list, err := s.Reports.ListAdvisorWorkloads(filter)
+	if err != nil || len(list) == 0 {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menyusun laporan beban dosen wali"})
+	}
+	finishWorkload(&list[0])
+
+	advisees, err := repository.GetLecturerAdvisees(s.PG, filter.LecturerID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil mahasiswa bimbingan"})
+	}
+	if advisees == nil {
+		advisees = []model.LecturerAdviseeResponse{}
+	}
+
+	overdue, err := s.Reports.ListOverdueForAdvisor(filter.LecturerID, filter.DueHours)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil submission overdue"})
+	}
+	now := time.Now()
+	for i := range overdue {
+		overdue[i].HoursWaiting = int(now.Sub(overdue[i].SubmittedAt).Hours())
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: model.AdvisorWorkloadDetail{
+		AdvisorWorkload: list[0],
+		SLAHours:        filter.DueHours,
+		Advisees:        advisees,
+		OverdueItems:    overdue,
+		GeneratedAt:     now,
+	}})
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/utils"
+)
+
+const expiryBatchLimit = 200
+
+// certificationExpiryPolicy menentukan apakah points sertifikasi yang sudah
+// kedaluwarsa tetap dihitung (keep) atau dikeluarkan dari total aktif (exclude).
+func certificationExpiryPolicy() string {
+	if os.Getenv("CERTIFICATION_EXPIRY_POLICY") == model.CertificationExpiryKeep {
+		return model.CertificationExpiryKeep
+	}
+	return model.CertificationExpiryExclude
+}
+
+func certificationWarningDays() int {
+	return utils.GetEnvInt("CERTIFICATION_EXPIRY_WARNING_DAYS", 30)
+}
+
+// certificationHolders mengembalikan pemilik dan anggota tim sebuah sertifikasi.
+func certificationHolders(ach model.Achievement) []string {
+	holders := []string{ach.StudentID}
+	for _, m := range ach.TeamMembers {
+		if !contains(holders, m) {
+			holders = append(holders, m)
+		}
+	}
+	return holders
+}
+
+// notifyCertificationHolders mengirim notifikasi ke setiap pemegang sertifikasi
+// yang reference-nya masih aktif.
+func (s *AchievementService) notifyCertificationHolders(ach model.Achievement, notifType, title, message string) {
+	hex := ach.ID.Hex()
+	for _, studentID := range certificationHolders(ach) {
+		refID, err := s.PGRepo.FindActiveReferenceID(hex, studentID)
+		if err != nil {
+			continue
+		}
+		notify(s.Notif, studentID, notifType, title, message, &refID)
+	}
+}
+
+// CheckCertificationExpiry memperingatkan mahasiswa sebelum sertifikasi
+// kedaluwarsa dan menandai sertifikasi yang masa berlakunya sudah lewat.
+func (s *AchievementService) CheckCertificationExpiry(ctx context.Context) (warned, expired int, err error) {
+	now := time.Now()
+
+	warnBefore := now.AddDate(0, 0, certificationWarningDays())
+	expiring, err := s.Mongo.ListCertificationsToWarn(ctx, now, warnBefore, expiryBatchLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, ach := range expiring {
+		if err := s.Mongo.MarkExpiryWarned(ctx, ach.ID, now); err != nil {
+			log.Printf("Expiry warning %s failed: %v", ach.ID.Hex(), err)
+			continue
+		}
+		s.notifyCertificationHolders(ach, model.NotificationCertificationExpiring,
+			"Sertifikasi akan kedaluwarsa",
+			fmt.Sprintf("Sertifikasi \"%s\" berlaku sampai %s. Unggah sertifikasi perpanjangan dengan menautkan prestasi ini.",
+				ach.Title, ach.Details.ValidUntil.Format("02-01-2006")))
+		warned++
+	}
+
+	excludePoints := certificationExpiryPolicy() == model.CertificationExpiryExclude
+	overdue, err := s.Mongo.ListCertificationsToExpire(ctx, now, expiryBatchLimit)
+	if err != nil {
+		return warned, 0, err
+	}
+	for _, ach := range overdue {
+		ok, err := s.Mongo.MarkExpired(ctx, ach.ID, now, excludePoints)
+		if err != nil {
+			log.Printf("Expire certification %s failed: %v", ach.ID.Hex(), err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		message := fmt.Sprintf("Sertifikasi \"%s\" sudah kedaluwarsa.", ach.Title)
+		if excludePoints {
+			message += " Points-nya tidak lagi dihitung dalam total aktif."
+		}
+		s.notifyCertificationHolders(ach, model.NotificationCertificationExpired, "Sertifikasi kedaluwarsa", message)
+		expired++
+	}
+
+	return warned, expired, nil
+}
+
+// resolveRenewal memeriksa bahwa prestasi yang diperpanjang adalah sertifikasi
+// milik mahasiswa yang sama, lalu mengembalikan id dokumen MongoDB aslinya.
+func (s *AchievementService) resolveRenewal(ctx context.Context, studentID, achType, originalRefID string) (string, []model.FieldError) {
+	if achType != model.AchievementTypeCertification {
+		return "", []model.FieldError{{Field: "renewal_of", Message: "hanya untuk prestasi sertifikasi"}}
+	}
+
+	ref, err := s.PGRepo.GetReference(originalRefID)
+	if err != nil || ref.StudentID != studentID || ref.ReferenceStatus == "deleted" {
+		return "", []model.FieldError{{Field: "renewal_of", Message: "prestasi asal tidak ditemukan"}}
+	}
+
+	original, err := s.Mongo.FindByHexID(ctx, ref.MongoID)
+	if err != nil || original.AchievementType != model.AchievementTypeCertification {
+		return "", []model.FieldError{{Field: "renewal_of", Message: "prestasi asal bukan sertifikasi"}}
+	}
+
+	return ref.MongoID, nil
+}
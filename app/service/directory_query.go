@@ -0,0 +1,51 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"go-fiber/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseDirectoryQuery membaca parameter umum daftar pengguna, mahasiswa, dan
+// dosen: q, is_active, sort, order, page, dan limit.
+func parseDirectoryQuery(c *fiber.Ctx, sortFields []string, defaultSort string) (model.DirectoryFilter, []model.FieldError) {
+	var errs []model.FieldError
+	f := model.DirectoryFilter{
+		Search: strings.TrimSpace(c.Query("q")),
+		Sort:   c.Query("sort", defaultSort),
+	}
+
+	if !contains(sortFields, f.Sort) {
+		errs = append(errs, model.FieldError{Field: "sort", Message: "harus salah satu dari: " + strings.Join(sortFields, ", ")})
+	}
+
+	// Urutan bawaan: terbaru lebih dulu untuk tanggal, abjad untuk kolom lain.
+	defaultOrder := "asc"
+	if f.Sort == "created_at" {
+		defaultOrder = "desc"
+	}
+	f.Order = strings.ToLower(c.Query("order", defaultOrder))
+	if f.Order != "asc" && f.Order != "desc" {
+		errs = append(errs, model.FieldError{Field: "order", Message: "harus asc atau desc"})
+	}
+
+	if raw := c.Query("is_active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, model.FieldError{Field: "is_active", Message: "harus true atau false"})
+		} else {
+			f.IsActive = &active
+		}
+	}
+
+	f.Page = parsePositiveQuery(c, "page", 1, &errs)
+	f.Limit = parsePositiveQuery(c, "limit", defaultListLimit, &errs)
+	if f.Limit > maxListLimit {
+		f.Limit = maxListLimit
+	}
+
+	return f, errs
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var errVersionMismatch = errors.New("versi prestasi sudah berubah")
+
+const versionMismatchMessage = "Prestasi sudah diubah oleh proses lain, muat ulang data"
+
+// entityVersion adalah isi ETag prestasi: versi reference di PostgreSQL dan
+// versi dokumen di MongoDB.
+type entityVersion struct {
+	Ref int
+	Doc int
+}
+
+func achievementETag(refVersion, docVersion int) string {
+	return fmt.Sprintf(`"%d.%d"`, refVersion, docVersion)
+}
+
+// parseETag membaca nilai ETag/If-Match. Nilai "*" menghasilkan nil yang
+// berarti versi apa pun diterima.
+func parseETag(value string) (*entityVersion, *reviewError) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, &reviewError{Status: 428, Message: "Header If-Match wajib diisi"}
+	}
+	if value == "*" {
+		return nil, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	var v entityVersion
+	if _, err := fmt.Sscanf(strings.Trim(value, `"`), "%d.%d", &v.Ref, &v.Doc); err != nil {
+		return nil, &reviewError{Status: 400, Message: "Format If-Match tidak valid"}
+	}
+	return &v, nil
+}
+
+func requireIfMatch(c *fiber.Ctx) (*entityVersion, *reviewError) {
+	return parseETag(c.Get(fiber.HeaderIfMatch))
+}
+
+func (v *entityVersion) matchesRef(refVersion int) bool {
+	return v == nil || v.Ref == refVersion
+}
+
+func (v *entityVersion) matchesDoc(docVersion int) bool {
+	return v == nil || v.Doc == docVersion
+}
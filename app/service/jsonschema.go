@@ -0,0 +1,369 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-fiber/app/model"
+)
+
+// Subset JSON Schema (draft 7) yang didukung untuk details prestasi:
+// type, enum, const, required, properties, additionalProperties, items,
+// minItems, maxItems, uniqueItems, minLength, maxLength, pattern, format,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum.
+var schemaKeywords = map[string]bool{
+	"$schema": true, "$id": true, "title": true, "description": true, "default": true, "examples": true,
+	"type": true, "enum": true, "const": true, "required": true, "properties": true,
+	"additionalProperties": true, "items": true, "minItems": true, "maxItems": true, "uniqueItems": true,
+	"minLength": true, "maxLength": true, "pattern": true, "format": true,
+	"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true,
+}
+
+var schemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true, "integer": true, "boolean": true, "null": true,
+}
+
+var schemaFormats = map[string]bool{
+	"date": true, "date-time": true, "email": true, "uri": true,
+}
+
+// checkJSONSchema memastikan schema hanya memakai keyword yang didukung dan
+// root-nya bertipe object.
+func checkJSONSchema(schema map[string]interface{}) error {
+	if t, _ := schema["type"].(string); t != "object" {
+		return errors.New("schema root harus bertipe object")
+	}
+	return checkSchemaNode(schema, "#")
+}
+
+func checkSchemaNode(node map[string]interface{}, path string) error {
+	for key, val := range node {
+		if !schemaKeywords[key] {
+			return fmt.Errorf("%s: keyword %q tidak didukung", path, key)
+		}
+
+		switch key {
+		case "type":
+			var types []interface{}
+			switch t := val.(type) {
+			case string:
+				types = []interface{}{t}
+			case []interface{}:
+				types = t
+			default:
+				return fmt.Errorf("%s/type harus string atau array", path)
+			}
+			for _, t := range types {
+				s, ok := t.(string)
+				if !ok || !schemaTypes[s] {
+					return fmt.Errorf("%s/type %v tidak dikenal", path, t)
+				}
+			}
+		case "enum":
+			if list, ok := val.([]interface{}); !ok || len(list) == 0 {
+				return fmt.Errorf("%s/enum harus array tidak kosong", path)
+			}
+		case "required":
+			list, ok := val.([]interface{})
+			if !ok {
+				return fmt.Errorf("%s/required harus array", path)
+			}
+			for _, r := range list {
+				if _, ok := r.(string); !ok {
+					return fmt.Errorf("%s/required harus berisi string", path)
+				}
+			}
+		case "properties":
+			props, ok := val.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s/properties harus object", path)
+			}
+			for name, sub := range props {
+				subSchema, ok := sub.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%s/properties/%s harus object", path, name)
+				}
+				if err := checkSchemaNode(subSchema, path+"/properties/"+name); err != nil {
+					return err
+				}
+			}
+		case "additionalProperties", "items":
+			switch sub := val.(type) {
+			case bool:
+				if key == "items" {
+					return fmt.Errorf("%s/items harus object", path)
+				}
+			case map[string]interface{}:
+				if err := checkSchemaNode(sub, path+"/"+key); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%s/%s harus object", path, key)
+			}
+		case "minItems", "maxItems", "minLength", "maxLength":
+			f, ok := val.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return fmt.Errorf("%s/%s harus bilangan bulat >= 0", path, key)
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			if _, ok := val.(float64); !ok {
+				return fmt.Errorf("%s/%s harus angka", path, key)
+			}
+		case "uniqueItems":
+			if _, ok := val.(bool); !ok {
+				return fmt.Errorf("%s/uniqueItems harus boolean", path)
+			}
+		case "pattern":
+			s, ok := val.(string)
+			if !ok {
+				return fmt.Errorf("%s/pattern harus string", path)
+			}
+			if _, err := regexp.Compile(s); err != nil {
+				return fmt.Errorf("%s/pattern tidak valid: %v", path, err)
+			}
+		case "format":
+			s, ok := val.(string)
+			if !ok || !schemaFormats[s] {
+				return fmt.Errorf("%s/format %v tidak didukung", path, val)
+			}
+		}
+	}
+	return nil
+}
+
+// validateJSONSchema memvalidasi value terhadap schema dan mengembalikan
+// error per field dengan path diawali prefix.
+func validateJSONSchema(schema map[string]interface{}, value interface{}, prefix string) []model.FieldError {
+	var errs []model.FieldError
+	validateSchemaNode(schema, value, prefix, &errs)
+	return errs
+}
+
+func validateSchemaNode(schema map[string]interface{}, value interface{}, path string, errs *[]model.FieldError) {
+	fail := func(msg string) {
+		*errs = append(*errs, model.FieldError{Field: path, Message: msg})
+	}
+
+	if t, ok := schema["type"]; ok && !matchesSchemaType(t, value) {
+		fail("harus bertipe " + describeSchemaType(t))
+		return
+	}
+
+	if c, ok := schema["const"]; ok && !jsonEqual(c, value) {
+		fail(fmt.Sprintf("harus bernilai %v", c))
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			opts := make([]string, len(enum))
+			for i, e := range enum {
+				opts[i] = fmt.Sprint(e)
+			}
+			fail("harus salah satu dari: " + strings.Join(opts, ", "))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateSchemaObject(schema, v, path, errs)
+	case []interface{}:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(v)) < min {
+			fail(fmt.Sprintf("minimal berisi %d item", int(min)))
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(v)) > max {
+			fail(fmt.Sprintf("maksimal berisi %d item", int(max)))
+		}
+		if unique, _ := schema["uniqueItems"].(bool); unique {
+			for i := range v {
+				for j := i + 1; j < len(v); j++ {
+					if jsonEqual(v[i], v[j]) {
+						fail("item tidak boleh duplikat")
+						i = len(v)
+						break
+					}
+				}
+			}
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateSchemaNode(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case string:
+		n := float64(utf8.RuneCountInString(v))
+		if min, ok := schema["minLength"].(float64); ok && n < min {
+			fail(fmt.Sprintf("minimal %d karakter", int(min)))
+		}
+		if max, ok := schema["maxLength"].(float64); ok && n > max {
+			fail(fmt.Sprintf("maksimal %d karakter", int(max)))
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				fail("format tidak sesuai pola " + pattern)
+			}
+		}
+		if format, ok := schema["format"].(string); ok && !matchesSchemaFormat(format, v) {
+			fail("format " + format + " tidak valid")
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			fail(fmt.Sprintf("minimal %v", min))
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			fail(fmt.Sprintf("maksimal %v", max))
+		}
+		if min, ok := schema["exclusiveMinimum"].(float64); ok && v <= min {
+			fail(fmt.Sprintf("harus lebih dari %v", min))
+		}
+		if max, ok := schema["exclusiveMaximum"].(float64); ok && v >= max {
+			fail(fmt.Sprintf("harus kurang dari %v", max))
+		}
+	}
+}
+
+func validateSchemaObject(schema map[string]interface{}, obj map[string]interface{}, path string, errs *[]model.FieldError) {
+	props, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if v, ok := obj[name]; !ok || v == nil {
+				*errs = append(*errs, model.FieldError{Field: path + "." + name, Message: "wajib diisi"})
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := path + "." + k
+		if sub, ok := props[k].(map[string]interface{}); ok {
+			validateSchemaNode(sub, obj[k], childPath, errs)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				*errs = append(*errs, model.FieldError{Field: childPath, Message: "field tidak dikenal"})
+			}
+		case map[string]interface{}:
+			validateSchemaNode(extra, obj[k], childPath, errs)
+		}
+	}
+}
+
+func matchesSchemaType(t interface{}, value interface{}) bool {
+	switch tt := t.(type) {
+	case string:
+		return matchesSingleType(tt, value)
+	case []interface{}:
+		for _, item := range tt {
+			if s, ok := item.(string); ok && matchesSingleType(s, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesSingleType(t string, value interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func describeSchemaType(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, " atau ")
+	}
+	return fmt.Sprint(t)
+}
+
+func matchesSchemaFormat(format, v string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", v)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(v)
+		return err == nil
+	case "uri":
+		u, err := url.Parse(v)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	}
+	return true
+}
+
+func jsonEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if !jsonEqual(v, bv[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
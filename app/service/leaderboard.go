@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fiber/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// Urutan pemecah seri, ditampilkan juga di response agar aturan terbuka.
+var leaderboardTieBreakers = []string{
+	"points desc",
+	"verified_count desc",
+	"last_verified_at asc",
+	"nim asc",
+}
+
+// semesterRange mengubah label "2025/2026 Ganjil" atau "2025/2026 Genap"
+// menjadi rentang [from, to). Kebalikan dari academicSemester.
+func semesterRange(label string) (time.Time, time.Time, bool) {
+	parts := strings.Fields(label)
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	years := strings.Split(parts[0], "/")
+	if len(years) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	start, err1 := strconv.Atoi(years[0])
+	end, err2 := strconv.Atoi(years[1])
+	if err1 != nil || err2 != nil || end != start+1 || start < 1900 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	switch strings.ToLower(parts[1]) {
+	case "ganjil":
+		return time.Date(start, time.August, 1, 0, 0, 0, 0, time.Local), time.Date(end, time.February, 1, 0, 0, 0, 0, time.Local), true
+	case "genap":
+		return time.Date(end, time.February, 1, 0, 0, 0, 0, time.Local), time.Date(end, time.August, 1, 0, 0, 0, 0, time.Local), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// parseLeaderboardPeriod menentukan periode dari salah satu: year, semester,
+// atau rentang from/to. Tanpa parameter, seluruh waktu dihitung.
+func parseLeaderboardPeriod(c *fiber.Ctx, f *model.ReportFilter, errs *[]model.FieldError) {
+	year := strings.TrimSpace(c.Query("year"))
+	semester := strings.TrimSpace(c.Query("semester"))
+	f.From = parseListDate(c, "from", false, errs)
+	f.To = parseListDate(c, "to", true, errs)
+	checkDateRange(f.From, f.To, "to", errs)
+
+	set := 0
+	for _, v := range []bool{year != "", semester != "", f.From != nil || f.To != nil} {
+		if v {
+			set++
+		}
+	}
+	if set > 1 {
+		*errs = append(*errs, model.FieldError{Field: "period", Message: "gunakan salah satu dari year, semester, atau from/to"})
+		return
+	}
+
+	switch {
+	case year != "":
+		y, err := strconv.Atoi(year)
+		if err != nil || y < 1900 || y > 9999 {
+			*errs = append(*errs, model.FieldError{Field: "year", Message: "harus tahun yang valid"})
+			return
+		}
+		from := time.Date(y, time.January, 1, 0, 0, 0, 0, time.Local)
+		to := from.AddDate(1, 0, 0)
+		f.From, f.To = &from, &to
+	case semester != "":
+		from, to, ok := semesterRange(semester)
+		if !ok {
+			*errs = append(*errs, model.FieldError{Field: "semester", Message: "format harus seperti 2025/2026 Ganjil atau 2025/2026 Genap"})
+			return
+		}
+		f.From, f.To = &from, &to
+	}
+}
+
+// rankLeaderboard menjumlahkan poin per mahasiswa lalu menyusun peringkat per
+// partisi. Mahasiswa yang memilih opt-out tidak ditampilkan dan tidak
+// menempati peringkat.
+func rankLeaderboard(rows []reportRow, partitionBy string, limit int) []model.LeaderboardPartition {
+	type standing struct {
+		partition string
+		entry     model.LeaderboardEntry
+	}
+	standings := map[string]*standing{}
+	for _, r := range rows {
+		if r.Key.OptOut {
+			continue
+		}
+		points := countedPoints(r.Key.ReferenceID, r.Key.Status, r.Doc)
+		st, ok := standings[r.Key.StudentID]
+		if !ok {
+			key := r.Key.StudyProgram
+			if partitionBy == model.LeaderboardByYearOfEntry {
+				key = ""
+				if r.Key.YearOfEntry > 0 {
+					key = strconv.Itoa(r.Key.YearOfEntry)
+				}
+			}
+			if key == "" {
+				key = "-"
+			}
+			st = &standing{partition: key, entry: model.LeaderboardEntry{
+				StudentID:    r.Key.StudentID,
+				NIM:          r.Key.StudentNIM,
+				FullName:     r.Key.StudentName,
+				StudyProgram: r.Key.StudyProgram,
+				YearOfEntry:  r.Key.YearOfEntry,
+			}}
+			standings[r.Key.StudentID] = st
+		}
+		st.entry.Points += points
+		st.entry.VerifiedCount++
+		if v := r.Key.VerifiedAt; points > 0 && v != nil && (st.entry.LastVerifiedAt == nil || v.After(*st.entry.LastVerifiedAt)) {
+			st.entry.LastVerifiedAt = v
+		}
+	}
+
+	grouped := map[string][]model.LeaderboardEntry{}
+	for _, st := range standings {
+		if st.entry.Points <= 0 {
+			continue
+		}
+		grouped[st.partition] = append(grouped[st.partition], st.entry)
+	}
+
+	partitions := make([]model.LeaderboardPartition, 0, len(grouped))
+	for key, entries := range grouped {
+		sort.Slice(entries, func(i, j int) bool {
+			a, b := entries[i], entries[j]
+			if a.Points != b.Points {
+				return a.Points > b.Points
+			}
+			if a.VerifiedCount != b.VerifiedCount {
+				return a.VerifiedCount > b.VerifiedCount
+			}
+			// Yang lebih dulu mencapai total poinnya diunggulkan.
+			if a.LastVerifiedAt != nil && b.LastVerifiedAt != nil && !a.LastVerifiedAt.Equal(*b.LastVerifiedAt) {
+				return a.LastVerifiedAt.Before(*b.LastVerifiedAt)
+			}
+			return a.NIM < b.NIM
+		})
+		if len(entries) > limit {
+			entries = entries[:limit]
+		}
+		for i := range entries {
+			entries[i].Rank = i + 1
+		}
+		partitions = append(partitions, model.LeaderboardPartition{Key: key, Entries: entries})
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Key < partitions[j].Key })
+	return partitions
+}
+
+// LeaderboardService menyusun peringkat mahasiswa berdasarkan poin verified,
+// dipartisi per program studi atau tahun masuk.
+func (s *ReportService) LeaderboardService(c *fiber.Ctx) error {
+	var errs []model.FieldError
+	filter := model.ReportFilter{
+		Statuses:        []string{"verified"},
+		StudyProgram:    strings.TrimSpace(c.Query("study_program")),
+		AchievementType: strings.TrimSpace(c.Query("achievement_type")),
+	}
+	filter.YearOfEntry = parsePositiveQuery(c, "year_of_entry", 0, &errs)
+	parseLeaderboardPeriod(c, &filter, &errs)
+
+	partitionBy := c.Query("partition", model.LeaderboardByStudyProgram)
+	if partitionBy != model.LeaderboardByStudyProgram && partitionBy != model.LeaderboardByYearOfEntry {
+		errs = append(errs, model.FieldError{Field: "partition", Message: "harus study_program atau year_of_entry"})
+	}
+	limit := parsePositiveQuery(c, "limit", defaultLeaderboardLimit, &errs)
+	if limit > maxLeaderboardLimit {
+		errs = append(errs, model.FieldError{Field: "limit", Message: fmt.Sprintf("maksimal %d", maxLeaderboardLimit)})
+	}
+	if len(errs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: errs})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+
+	rows, missing, err := s.loadReportRows(ctx, filter, bson.M{
+		"achievementType":   1,
+		"details.eventDate": 1,
+		"points":            1,
+		"referencePoints":   1,
+		"pointsExcluded":    1,
+		"createdAt":         1,
+	})
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menyusun leaderboard"})
+	}
+	if len(missing) > 0 {
+		log.Printf("Leaderboard: skipped %d references with missing documents", len(missing))
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: model.Leaderboard{
+		PartitionBy: partitionBy,
+		From:        filter.From,
+		To:          filter.To,
+		TieBreakers: leaderboardTieBreakers,
+		Partitions:  rankLeaderboard(rows, partitionBy, limit),
+		GeneratedAt: time.Now(),
+	}})
+}
+
+// LeaderboardOptOutService mengatur apakah mahasiswa yang login ditampilkan
+// di leaderboard. User tanpa data mahasiswa mendapat 404.
+func (s *ReportService) LeaderboardOptOutService(c *fiber.Ctx) error {
+	var req model.LeaderboardOptOutRequest
+	if err := c.BodyParser(&req); err != nil || req.OptOut == nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Field opt_out wajib diisi"})
+	}
+
+	err := s.Reports.SetLeaderboardOptOut(getUserID(c), *req.OptOut)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Data mahasiswa tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menyimpan pengaturan leaderboard"})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "Pengaturan leaderboard disimpan",
+		Data:    fiber.Map{"opt_out": *req.OptOut},
+	})
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go-fiber/app/model"
+)
+
+func TestSemesterRange(t *testing.T) {
+	date := func(y int, m time.Month) time.Time { return time.Date(y, m, 1, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		label    string
+		from, to time.Time
+		ok       bool
+	}{
+		{"2025/2026 Ganjil", date(2025, time.August), date(2026, time.February), true},
+		{"2025/2026 Genap", date(2026, time.February), date(2026, time.August), true},
+		{"2025/2026 genap", date(2026, time.February), date(2026, time.August), true},
+		{"  2025/2026   GANJIL ", date(2025, time.August), date(2026, time.February), true},
+		{"2025/2027 Ganjil", time.Time{}, time.Time{}, false},
+		{"2026/2025 Genap", time.Time{}, time.Time{}, false},
+		{"2025-2026 Ganjil", time.Time{}, time.Time{}, false},
+		{"2025/2026", time.Time{}, time.Time{}, false},
+		{"2025/2026 Pendek", time.Time{}, time.Time{}, false},
+		{"1800/1801 Ganjil", time.Time{}, time.Time{}, false},
+		{"abcd/2026 Ganjil", time.Time{}, time.Time{}, false},
+		{"", time.Time{}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		from, to, ok := semesterRange(tt.label)
+		if ok != tt.ok || !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("semesterRange(%q) = %v, %v, %v; want %v, %v, %v", tt.label, from, to, ok, tt.from, tt.to, tt.ok)
+		}
+	}
+}
+
+func TestSemesterRangeContainsAcademicSemester(t *testing.T) {
+	dates := []time.Time{
+		time.Date(2025, time.August, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2025, time.December, 31, 23, 59, 59, 0, time.Local),
+		time.Date(2026, time.January, 31, 23, 59, 59, 0, time.Local),
+		time.Date(2026, time.February, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2026, time.July, 31, 23, 59, 59, 0, time.Local),
+	}
+
+	for _, d := range dates {
+		label := academicSemester(d)
+		from, to, ok := semesterRange(label)
+		if !ok {
+			t.Errorf("semesterRange(%q) tidak valid untuk %v", label, d)
+			continue
+		}
+		if d.Before(from) || !d.Before(to) {
+			t.Errorf("%v berada di luar rentang %q [%v, %v)", d, label, from, to)
+		}
+	}
+}
+
+func TestRankLeaderboard(t *testing.T) {
+	at := func(day int) *time.Time {
+		v := time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	type ref struct {
+		student, nim, program string
+		year, points          int
+		verifiedAt            *time.Time
+		optOut, excluded      bool
+	}
+	build := func(refs []ref) []reportRow {
+		rows := make([]reportRow, len(refs))
+		for i, r := range refs {
+			refID := r.student + "-" + string(rune('a'+i))
+			rows[i] = reportRow{
+				Key: model.ReportKey{
+					ReferenceID:  refID,
+					StudentID:    r.student,
+					StudentNIM:   r.nim,
+					Status:       "verified",
+					StudyProgram: r.program,
+					YearOfEntry:  r.year,
+					OptOut:       r.optOut,
+					VerifiedAt:   r.verifiedAt,
+				},
+				Doc: model.Achievement{
+					ReferencePoints: map[string]int{refID: r.points},
+					PointsExcluded:  r.excluded,
+				},
+			}
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name        string
+		refs        []ref
+		partitionBy string
+		limit       int
+		want        map[string][]string
+	}{
+		{
+			name: "points desc per program, empty program grouped as -",
+			refs: []ref{
+				{student: "s1", nim: "001", program: "TI", points: 10, verifiedAt: at(1)},
+				{student: "s2", nim: "002", program: "TI", points: 30, verifiedAt: at(1)},
+				{student: "s3", nim: "003", program: "SI", points: 5, verifiedAt: at(1)},
+				{student: "s4", nim: "004", program: "", points: 5, verifiedAt: at(1)},
+			},
+			partitionBy: model.LeaderboardByStudyProgram,
+			limit:       10,
+			want:        map[string][]string{"-": {"004"}, "SI": {"003"}, "TI": {"002", "001"}},
+		},
+		{
+			name: "points summed per student",
+			refs: []ref{
+				{student: "s1", nim: "001", program: "TI", points: 10, verifiedAt: at(1)},
+				{student: "s1", nim: "001", program: "TI", points: 15, verifiedAt: at(2)},
+				{student: "s2", nim: "002", program: "TI", points: 20, verifiedAt: at(1)},
+			},
+			partitionBy: model.LeaderboardByStudyProgram,
+			limit:       10,
+			want:        map[string][]string{"TI": {"001", "002"}},
+		},
+		{
+			name: "tie on points broken by verified count",
+			refs: []ref{
+				{student: "s1", nim: "001", program: "TI", points: 20, verifiedAt: at(1)},
+				{student: "s2", nim: "002", program: "TI", points: 10, verifiedAt: at(2)},
+				{student: "s2", nim: "002", program: "TI", points: 10, verifiedAt: at(3)},
+			},
+			partitionBy: model.LeaderboardByStudyProgram,
+			limit:       10,
+			want:        map[string][]string{"TI": {"002", "001"}},
+		},
+		{
+			name: "tie on points and count broken by earlier last verification",
+			refs: []ref{
+				{student: "s1", nim: "001", program: "TI", points: 10, verifiedAt: at(5)},
+				{student: "s2", nim: "002", program: "TI", points: 10, verifiedAt: at(3)},
+			},
+			partitionBy: model.LeaderboardByStudyProgram,
+			limit:       10,
+			want:        map[string][]string{"TI": {"002", "001"}},
+		},
+		{
+			name: "full tie broken by nim",
+			refs: []ref{
+				{student: "s2", nim: "002", program: "TI", points: 10, verifiedAt: at(3)},
+				{student: "s1", nim: "001", program: "TI", points: 10, verifiedAt: at(3)},
+			},
+			partitionBy: model.LeaderboardByStudyProgram,
+			limit:       10,
+			want:        map[string][]string{"TI": {"001", "002"}},
+		},
+		{
+			name: "opt-out and zero points do not take a rank",
+			refs: []ref{
+				{student: "s1", nim: "001", program: "TI", points: 50, verifiedAt: at(1), optOut: true},
+				{student: "s2", nim: "002", program: "TI", points: 40, verifiedAt: at(1), excluded: true},
+				{student: "s3", nim: "003", program: "TI", points: 0, verifiedAt: at(1)},
+				{student: "s4", nim: "004", program: "TI", points: 10, verifiedAt: at(1)},
+			},
+			partitionBy: model.LeaderboardByStudyProgram,
+			limit:       10,
+			want:        map[string][]string{"TI": {"004"}},
+		},
+		{
+			name: "limit per partition",
+			refs: []ref{
+				{student: "s1", nim: "001", program: "TI", points: 10, verifiedAt: at(1)},
+				{student: "s2", nim: "002", program: "TI", points: 20, verifiedAt: at(1)},
+				{student: "s3", nim: "003", program: "TI", points: 30, verifiedAt: at(1)},
+				{student: "s4", nim: "004", program: "SI", points: 5, verifiedAt: at(1)},
+			},
+			partitionBy: model.LeaderboardByStudyProgram,
+			limit:       2,
+			want:        map[string][]string{"SI": {"004"}, "TI": {"003", "002"}},
+		},
+		{
+			name: "partition by year of entry",
+			refs: []ref{
+				{student: "s1", nim: "001", program: "TI", year: 2023, points: 10, verifiedAt: at(1)},
+				{student: "s2", nim: "002", program: "SI", year: 2023, points: 20, verifiedAt: at(1)},
+				{student: "s3", nim: "003", program: "TI", year: 0, points: 5, verifiedAt: at(1)},
+			},
+			partitionBy: model.LeaderboardByYearOfEntry,
+			limit:       10,
+			want:        map[string][]string{"-": {"003"}, "2023": {"002", "001"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partitions := rankLeaderboard(build(tt.refs), tt.partitionBy, tt.limit)
+
+			got := map[string][]string{}
+			var keys []string
+			for _, p := range partitions {
+				keys = append(keys, p.Key)
+				for i, e := range p.Entries {
+					if e.Rank != i+1 {
+						t.Errorf("partisi %s: %s rank %d, want %d", p.Key, e.NIM, e.Rank, i+1)
+					}
+					got[p.Key] = append(got[p.Key], e.NIM)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rankLeaderboard = %v, want %v", got, tt.want)
+			}
+			for i := 1; i < len(keys); i++ {
+				if keys[i-1] >= keys[i] {
+					t.Errorf("partisi tidak terurut: %v", keys)
+				}
+			}
+		})
+	}
+}
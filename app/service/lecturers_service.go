@@ -4,12 +4,23 @@ import (
 	"database/sql"
 	"go-fiber/app/model"
 	"go-fiber/app/repository"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func GetAllLecturersService(c *fiber.Ctx, db *sql.DB) error {
-	lecturers, err := repository.GetAllLecturers(db)
+	filter, fieldErrs := parseDirectoryQuery(c, repository.LecturerSortFields(), "full_name")
+	filter.Department = strings.TrimSpace(c.Query("department"))
+	if len(fieldErrs) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Parameter query tidak valid",
+			Data:   fieldErrs,
+		})
+	}
+
+	lecturers, total, err := repository.GetAllLecturers(db, filter)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(model.APIResponse{
 			Status: "error",
@@ -20,6 +31,7 @@ func GetAllLecturersService(c *fiber.Ctx, db *sql.DB) error {
 	return c.JSON(model.APIResponse{
 		Status: "success",
 		Data:   lecturers,
+		Meta:   model.NewPageMeta(filter.Page, filter.Limit, total),
 	})
 }
 
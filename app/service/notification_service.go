@@ -0,0 +1,59 @@
+package service
+
+import (
+	"database/sql"
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationService struct {
+	Repo *repository.NotificationRepo
+}
+
+func NewNotificationService(pg *sql.DB) *NotificationService {
+	return &NotificationService{
+		Repo: repository.NewNotificationRepo(pg),
+	}
+}
+
+func (s *NotificationService) ListNotificationsService(c *fiber.Ctx) error {
+	list, err := s.Repo.ListForUser(getUserID(c), c.QueryBool("unread"))
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil notifikasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: list})
+}
+
+func (s *NotificationService) MarkReadService(c *fiber.Ctx) error {
+	err := s.Repo.MarkRead(c.Params("id"), getUserID(c))
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Notifikasi tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memperbarui notifikasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Message: "Notifikasi ditandai sudah dibaca"})
+}
+
+func (s *NotificationService) MarkAllReadService(c *fiber.Ctx) error {
+	if err := s.Repo.MarkAllRead(getUserID(c)); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal memperbarui notifikasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Message: "Semua notifikasi ditandai sudah dibaca"})
+}
+
+// notify mengirim notifikasi tanpa menggagalkan proses utama; kegagalan hanya dicatat di log.
+func notify(repo *repository.NotificationRepo, userID, notifType, title, message string, referenceID *string) {
+	if userID == "" {
+		return
+	}
+	if err := repo.Create(userID, notifType, title, message, referenceID); err != nil {
+		log.Printf("Failed to create notification %s for %s: %v", notifType, userID, err)
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"go-fiber/app/model"
+	"go-fiber/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// Event baru diberi jeda sebelum bisa diambil worker agar proses inline
+	// setelah commit sempat menyelesaikannya lebih dulu.
+	outboxInlineDelaySeconds = 60
+	outboxLeaseSeconds       = 120
+	outboxBatchLimit         = 100
+	outboxMaxBackoffSeconds  = 3600
+)
+
+// errOutboxSuperseded menandai event yang tidak lagi berlaku karena reference
+// sudah berubah setelah event dibuat. Event seperti ini diselesaikan tanpa
+// menyentuh MongoDB.
+var errOutboxSuperseded = errors.New("event dilewati karena reference sudah berubah")
+
+func outboxMaxAttempts() int {
+	return utils.GetEnvInt("OUTBOX_MAX_ATTEMPTS", 10)
+}
+
+// applyOutboxEvent menjalankan sisi MongoDB dari sebuah event. Setiap operasi
+// idempotent sehingga aman diulang oleh worker.
+func (s *AchievementService) applyOutboxEvent(ctx context.Context, eventType string, payload json.RawMessage) error {
+	switch eventType {
+	case model.OutboxAchievementCreate:
+		var p model.CreateAchievementPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		var ach model.Achievement
+		if err := bson.UnmarshalExtJSON(p.Document, true, &ach); err != nil {
+			return err
+		}
+		return s.Mongo.InsertWithID(ctx, ach)
+
+	case model.OutboxAchievementSetPoints:
+		var p model.SetPointsPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		if p.ReferenceID == "" || p.RefVersion <= 0 || p.Status == "" {
+			return errors.New("payload set_points tidak lengkap")
+		}
+		return s.applySetPoints(ctx, p)
+	}
+
+	return fmt.Errorf("event outbox tidak dikenal: %s", eventType)
+}
+
+// applySetPoints menulis points selama reference masih berada pada status dan
+// versi yang sama dengan saat event dibuat. Baris reference dikunci selama
+// penulisan sehingga perubahan status berikutnya menunggu points tersimpan.
+func (s *AchievementService) applySetPoints(ctx context.Context, p model.SetPointsPayload) error {
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, version, err := s.PGRepo.LockReference(tx, p.ReferenceID)
+	if err == sql.ErrNoRows {
+		return errOutboxSuperseded
+	}
+	if err != nil {
+		return err
+	}
+	if status != p.Status || version != p.RefVersion {
+		return errOutboxSuperseded
+	}
+
+	if err := s.Mongo.UpdateByHexID(ctx, p.MongoID, bson.M{"referencePoints." + p.ReferenceID: p.Points}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// compensateOutboxEvent membatalkan sisi PostgreSQL dari event yang tidak bisa
+// diselesaikan. Mengembalikan false jika event tidak punya kompensasi.
+func (s *AchievementService) compensateOutboxEvent(ctx context.Context, e model.OutboxEvent) (bool, error) {
+	switch e.EventType {
+	case model.OutboxAchievementCreate:
+		var p model.CreateAchievementPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return false, err
+		}
+		if _, err := s.Mongo.FindByHexID(ctx, p.MongoID); err == nil {
+			return false, nil
+		}
+		return true, s.PGRepo.DeleteOrphanReference(p.ReferenceID, p.MongoID)
+
+	case model.OutboxAchievementSetPoints:
+		var p model.SetPointsPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return false, err
+		}
+		if !p.RevertOnFailure {
+			return false, nil
+		}
+		// Hanya reference milik event ini yang dikembalikan; anggota tim lain
+		// punya event dan points sendiri.
+		_, err := s.PGRepo.RevertVerification(e.AggregateID, p.RefVersion, p.ActorID, "Verifikasi dibatalkan karena points gagal disimpan")
+		return err == nil, err
+	}
+
+	return false, nil
+}
+
+// runOutboxInline langsung menjalankan event yang baru di-commit. Jika gagal,
+// event dibiarkan pending dan segera tersedia untuk worker.
+func (s *AchievementService) runOutboxInline(ctx context.Context, eventID, eventType string, payload interface{}) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	err = s.applyOutboxEvent(ctx, eventType, raw)
+	if errors.Is(err, errOutboxSuperseded) {
+		if cerr := s.Outbox.Complete(eventID, "done", err.Error()); cerr != nil {
+			log.Printf("Failed to complete outbox event %s: %v", eventID, cerr)
+		}
+		return nil
+	}
+	if err != nil {
+		if rerr := s.Outbox.Retry(eventID, err.Error(), 0); rerr != nil {
+			log.Printf("Failed to reschedule outbox event %s: %v", eventID, rerr)
+		}
+		return err
+	}
+
+	if err := s.Outbox.Complete(eventID, "done", ""); err != nil {
+		log.Printf("Failed to complete outbox event %s: %v", eventID, err)
+	}
+	return nil
+}
+
+// ProcessOutbox menyelesaikan event yang tertunda. Event yang terus gagal
+// sampai batas percobaan dikompensasi, atau ditandai failed jika tidak bisa.
+func (s *AchievementService) ProcessOutbox(ctx context.Context) (done, failed int, err error) {
+	events, err := s.Outbox.Claim(outboxBatchLimit, outboxLeaseSeconds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, e := range events {
+		if ctx.Err() != nil {
+			return done, failed, ctx.Err()
+		}
+
+		applyErr := s.applyOutboxEvent(ctx, e.EventType, e.Payload)
+		if applyErr == nil || errors.Is(applyErr, errOutboxSuperseded) {
+			lastError := ""
+			if applyErr != nil {
+				lastError = applyErr.Error()
+			}
+			if err := s.Outbox.Complete(e.ID, "done", lastError); err != nil {
+				log.Printf("Failed to complete outbox event %s: %v", e.ID, err)
+			}
+			done++
+			continue
+		}
+
+		if e.Attempts < outboxMaxAttempts() {
+			backoff := e.Attempts * e.Attempts * 30
+			if backoff > outboxMaxBackoffSeconds {
+				backoff = outboxMaxBackoffSeconds
+			}
+			if err := s.Outbox.Retry(e.ID, applyErr.Error(), backoff); err != nil {
+				log.Printf("Failed to reschedule outbox event %s: %v", e.ID, err)
+			}
+			continue
+		}
+
+		compensated, err := s.compensateOutboxEvent(ctx, e)
+		if err != nil {
+			log.Printf("Outbox compensation %s failed: %v", e.ID, err)
+			_ = s.Outbox.Retry(e.ID, err.Error(), outboxMaxBackoffSeconds)
+			continue
+		}
+		status := "failed"
+		if compensated {
+			status = "compensated"
+		}
+		if err := s.Outbox.Complete(e.ID, status, applyErr.Error()); err != nil {
+			log.Printf("Failed to complete outbox event %s: %v", e.ID, err)
+		}
+		failed++
+	}
+
+	return done, failed, nil
+}
+
+// findDocument mengambil dokumen MongoDB sebuah reference. Dokumen yang belum
+// dibuat karena event create masih menunggu outbox menghasilkan 409 agar klien
+// mencoba lagi, bukan 500.
+func (s *AchievementService) findDocument(mongoHex string) (*model.Achievement, *reviewError) {
+	ach, err := s.Mongo.FindByHexID(context.Background(), mongoHex)
+	if err == nil {
+		return ach, nil
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if pending, perr := s.Outbox.HasPendingCreate(mongoHex); perr == nil && pending {
+			return nil, &reviewError{Status: 409, Message: "Prestasi sedang diproses, coba lagi beberapa saat lagi"}
+		}
+	}
+	return nil, &reviewError{Status: 500, Message: "Gagal mengambil data MongoDB"}
+}
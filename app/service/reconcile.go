@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-fiber/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Dokumen dan reference yang lebih muda dari batas ini mungkin masih dalam
+// proses pembuatan sehingga tidak dianggap orphan atau kehilangan dokumen.
+const reconcileOrphanMinAge = time.Hour
+
+// Reconcile mencocokkan achievement_references dengan achievement_records dan
+// melaporkan ketidaksesuaian. Jika opts.Repair aktif, perbaikan yang aman
+// dijalankan; dengan opts.DryRun perbaikan hanya dicantumkan tanpa dieksekusi.
+func (s *AchievementService) Reconcile(ctx context.Context, opts model.ReconcileOptions) (*model.ReconcileReport, error) {
+	report := &model.ReconcileReport{
+		StartedAt: time.Now(),
+		Repair:    opts.Repair,
+		DryRun:    opts.DryRun,
+		Summary:   map[string]int{},
+		Issues:    []model.ReconcileIssue{},
+	}
+
+	// Event pending dibaca lebih dulu: event yang selesai setelah pembacaan ini
+	// sudah terlihat saat MongoDB dibaca, sedangkan event yang dibuat setelahnya
+	// tertahan oleh batas umur reference (updated_at ikut berubah).
+	inFlight, err := s.Outbox.PendingMongoIDs()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := s.PGRepo.ListReferenceKeys()
+	if err != nil {
+		return nil, err
+	}
+	// Snapshot dibuat setelah dokumennya ada, jadi dibaca sebelum dokumen agar
+	// dokumen baru tidak terlihat hilang.
+	revisionDocs, err := s.Revisions.ListAchievementIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.Mongo.ListForReconcile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphanBefore := time.Now().Add(-reconcileOrphanMinAge)
+	report.ReferencesScanned = len(refs)
+	report.DocumentsScanned = len(docs)
+
+	docByID := make(map[string]model.Achievement, len(docs))
+	for _, d := range docs {
+		docByID[d.ID.Hex()] = d
+	}
+	refsByDoc := map[string][]model.ReferenceKey{}
+	for _, r := range refs {
+		refsByDoc[r.MongoID] = append(refsByDoc[r.MongoID], r)
+	}
+
+	add := func(issue model.ReconcileIssue, repair func() error) {
+		if opts.Repair && repair != nil {
+			if opts.DryRun {
+				issue.Action = "would " + issue.Action
+			} else if err := repair(); err != nil {
+				issue.Error = err.Error()
+			} else {
+				issue.Repaired = true
+			}
+		}
+		report.Summary[issue.Kind]++
+		report.Issues = append(report.Issues, issue)
+	}
+
+	for _, r := range refs {
+		if inFlight[r.MongoID] || r.UpdatedAt.After(orphanBefore) {
+			report.SkippedInFlight++
+			continue
+		}
+
+		doc, ok := docByID[r.MongoID]
+		if !ok {
+			issue := model.ReconcileIssue{
+				Kind:        model.ReconcileMissingDocument,
+				ReferenceID: r.ID,
+				MongoID:     r.MongoID,
+				StudentID:   r.StudentID,
+				Detail:      fmt.Sprintf("reference berstatus %s tidak memiliki dokumen MongoDB", r.Status),
+			}
+			var repair func() error
+			if r.Status == "draft" || r.Status == "deleted" {
+				ref := r
+				issue.Action = "delete reference"
+				repair = func() error { return s.PGRepo.DeleteOrphanReference(ref.ID, ref.MongoID) }
+			} else {
+				issue.Action = "manual review"
+			}
+			add(issue, repair)
+			continue
+		}
+
+		if doc.StudentID != r.StudentID && !contains(doc.TeamMembers, r.StudentID) {
+			issue := model.ReconcileIssue{
+				Kind:        model.ReconcileStudentMismatch,
+				ReferenceID: r.ID,
+				MongoID:     r.MongoID,
+				StudentID:   r.StudentID,
+				Detail:      fmt.Sprintf("studentId dokumen %s bukan pemilik reference maupun anggota tim", doc.StudentID),
+			}
+			var repair func() error
+			if len(refsByDoc[r.MongoID]) == 1 {
+				ref := r
+				issue.Action = "set document studentId"
+				repair = func() error {
+					return s.Mongo.UpdateByHexID(ctx, ref.MongoID, bson.M{"studentId": ref.StudentID})
+				}
+			} else {
+				issue.Action = "manual review"
+			}
+			add(issue, repair)
+		}
+	}
+
+	for hex, doc := range docByID {
+		if inFlight[hex] {
+			continue
+		}
+
+		docRefs := refsByDoc[hex]
+		if len(docRefs) == 0 {
+			if doc.CreatedAt.After(orphanBefore) {
+				continue
+			}
+			id := hex
+			add(model.ReconcileIssue{
+				Kind:      model.ReconcileOrphanDocument,
+				MongoID:   hex,
+				StudentID: doc.StudentID,
+				Detail:    "dokumen MongoDB tidak dirujuk oleh reference mana pun",
+				Action:    "delete document",
+			}, func() error { return s.Mongo.DeleteByHexID(ctx, id) })
+			continue
+		}
+
+		id := hex
+		verified := false
+		for _, r := range docRefs {
+			ref := r
+			points, recorded := doc.ReferencePoints[r.ID]
+			switch {
+			case r.Status == "verified":
+				verified = true
+				if doc.PointsFor(r.ID) > 0 || r.UpdatedAt.After(orphanBefore) {
+					continue
+				}
+				add(model.ReconcileIssue{
+					Kind:        model.ReconcileVerifiedWithoutPoints,
+					ReferenceID: r.ID,
+					MongoID:     hex,
+					StudentID:   r.StudentID,
+					Detail:      "reference verified tetapi points di MongoDB kosong",
+					Action:      "revert verification to submitted",
+				}, func() error {
+					// Hanya reference ini yang dikembalikan, dan hanya jika belum
+					// berubah sejak dibaca.
+					_, err := s.PGRepo.RevertVerification(ref.ID, ref.Version, "", "Dikembalikan oleh rekonsiliasi: points tidak ditemukan")
+					return err
+				})
+			case recorded && points > 0 && !r.UpdatedAt.After(orphanBefore):
+				add(model.ReconcileIssue{
+					Kind:        model.ReconcilePointsWithoutVerification,
+					ReferenceID: r.ID,
+					MongoID:     hex,
+					StudentID:   r.StudentID,
+					Detail:      fmt.Sprintf("points %d tersimpan untuk reference berstatus %s", points, r.Status),
+					Action:      "reset points",
+				}, func() error {
+					return s.Mongo.UpdateByHexID(ctx, id, bson.M{"referencePoints." + ref.ID: 0})
+				})
+			}
+		}
+
+		// Dokumen lama menyimpan points bersama di level dokumen.
+		if !verified && doc.Points > 0 {
+			add(model.ReconcileIssue{
+				Kind:    model.ReconcilePointsWithoutVerification,
+				MongoID: hex,
+				Detail:  fmt.Sprintf("points %d tersimpan tanpa reference verified", doc.Points),
+				Action:  "reset points",
+			}, func() error { return s.Mongo.UpdateByHexID(ctx, id, bson.M{"points": 0}) })
+		}
+	}
+
+	for _, hex := range revisionDocs {
+		if _, ok := docByID[hex]; ok || inFlight[hex] || len(refsByDoc[hex]) > 0 {
+			continue
+		}
+		id := hex
+		add(model.ReconcileIssue{
+			Kind:    model.ReconcileOrphanRevisions,
+			MongoID: hex,
+			Detail:  "snapshot revisi tersimpan untuk dokumen yang sudah tidak ada",
+			Action:  "delete revisions",
+		}, func() error {
+			_, err := s.Revisions.DeleteByAchievement(ctx, id)
+			return err
+		})
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (s *AchievementService) ReconcileService(c *fiber.Ctx) error {
+	var opts model.ReconcileOptions
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&opts); err != nil {
+			return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Body request tidak valid"})
+		}
+	}
+
+	report, err := s.Reconcile(context.Background(), opts)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menjalankan rekonsiliasi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: report})
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Batas waktu penyusunan satu laporan.
+const reportTimeout = 60 * time.Second
+
+// Field dokumen yang dibutuhkan laporan.
+var reportProjection = bson.M{
+	"title":                    1,
+	"achievementType":          1,
+	"details.competitionLevel": 1,
+	"details.medalType":        1,
+	"details.eventDate":        1,
+	"points":                   1,
+	"referencePoints":          1,
+	"pointsExcluded":           1,
+	"isTeam":                   1,
+	"createdAt":                1,
+}
+
+type ReportService struct {
+	Reports *repository.ReportRepo
+	Refs    *repository.AchievementRefRepo
+	Mongo   *repository.AchievementMongoRepo
+	Types   *repository.AchievementTypeRepo
+	SKPI    *repository.SKPIRepo
+	PG      *sql.DB
+}
+
+func NewReportService(pg *sql.DB, mongoDB *mongo.Database) *ReportService {
+	return &ReportService{
+		Reports: repository.NewReportRepo(pg),
+		Refs:    repository.NewAchievementRefRepo(pg),
+		Mongo:   repository.NewAchievementMongoRepo(mongoDB),
+		Types:   repository.NewAchievementTypeRepo(mongoDB),
+		SKPI:    repository.NewSKPIRepo(pg),
+		PG:      pg,
+	}
+}
+
+// reportRow adalah satu reference yang sudah digabung dengan dokumennya.
+type reportRow struct {
+	Key model.ReportKey
+	Doc model.Achievement
+}
+
+// achievementDate adalah tanggal acuan periode: tanggal kegiatan jika ada,
+// selain itu tanggal dokumen dibuat.
+func achievementDate(doc model.Achievement) time.Time {
+	if doc.Details.EventDate != nil {
+		return *doc.Details.EventDate
+	}
+	return doc.CreatedAt
+}
+
+// countedPoints adalah poin yang diakui: hanya prestasi verified dan tidak
+// dikecualikan karena sertifikasinya kedaluwarsa. Setiap reference memiliki
+// points sendiri, termasuk anggota prestasi tim.
+func countedPoints(refID, status string, doc model.Achievement) int {
+	if status != "verified" || doc.PointsExcluded {
+		return 0
+	}
+	return doc.PointsFor(refID)
+}
+
+// academicSemester mengembalikan label semester akademik. Semester ganjil
+// dimulai Agustus, semester genap dimulai Februari.
+func academicSemester(t time.Time) string {
+	y := t.Year()
+	switch m := t.Month(); {
+	case m >= time.August:
+		return fmt.Sprintf("%d/%d Ganjil", y, y+1)
+	case m == time.January:
+		return fmt.Sprintf("%d/%d Ganjil", y-1, y)
+	default:
+		return fmt.Sprintf("%d/%d Genap", y-1, y)
+	}
+}
+
+func periodKey(t time.Time, period string) string {
+	switch period {
+	case model.ReportPeriodMonth:
+		return t.Format("2006-01")
+	case model.ReportPeriodSemester:
+		return academicSemester(t)
+	default:
+		return strconv.Itoa(t.Year())
+	}
+}
+
+// loadReportRows mengambil reference dari PostgreSQL lalu dokumennya dari
+// MongoDB dalam satu query, kemudian menerapkan filter tipe dan tanggal.
+func (s *ReportService) loadReportRows(ctx context.Context, f model.ReportFilter, projection bson.M) ([]reportRow, []string, error) {
+	keys, err := s.Reports.ListReportKeys(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]string, len(keys))
+	for i, k := range keys {
+		ids[i] = k.MongoID
+	}
+	docs, err := s.Mongo.FindByHexIDs(ctx, ids, projection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []reportRow
+	var missing []string
+	for _, k := range keys {
+		doc, ok := docs[k.MongoID]
+		if !ok {
+			missing = append(missing, k.MongoID)
+			continue
+		}
+		if f.AchievementType != "" && doc.AchievementType != f.AchievementType {
+			continue
+		}
+		date := achievementDate(doc)
+		if f.From != nil && date.Before(*f.From) {
+			continue
+		}
+		if f.To != nil && !date.Before(*f.To) {
+			continue
+		}
+		rows = append(rows, reportRow{Key: k, Doc: doc})
+	}
+	return rows, missing, nil
+}
+
+// statGrouper menjumlahkan count dan points per key.
+type statGrouper map[string]*model.StatBucket
+
+func (g statGrouper) add(key string, points int) {
+	if key == "" {
+		key = "-"
+	}
+	b, ok := g[key]
+	if !ok {
+		b = &model.StatBucket{Key: key}
+		g[key] = b
+	}
+	b.Count++
+	b.Points += points
+}
+
+// sorted mengurutkan berdasarkan key (untuk dimensi waktu) atau berdasarkan
+// jumlah terbanyak.
+func (g statGrouper) sorted(byKey bool) []model.StatBucket {
+	out := make([]model.StatBucket, 0, len(g))
+	for _, b := range g {
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !byKey && out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// parseReportFilter membaca parameter umum laporan. Status bawaan adalah
+// verified karena hanya prestasi terverifikasi yang diakui institusi.
+func parseReportFilter(c *fiber.Ctx) (model.ReportFilter, []model.FieldError) {
+	var errs []model.FieldError
+	f := model.ReportFilter{
+		StudyProgram:    strings.TrimSpace(c.Query("study_program")),
+		AchievementType: strings.TrimSpace(c.Query("achievement_type")),
+		Period:          c.Query("period", model.ReportPeriodYear),
+	}
+
+	f.Statuses = parseStatusQuery(c, "Admin", &errs)
+	if len(f.Statuses) == 0 {
+		f.Statuses = []string{"verified"}
+	}
+	if contains(f.Statuses, "deleted") {
+		errs = append(errs, model.FieldError{Field: "status", Message: "status deleted tidak termasuk laporan"})
+	}
+
+	switch f.Period {
+	case model.ReportPeriodYear, model.ReportPeriodSemester, model.ReportPeriodMonth:
+	default:
+		errs = append(errs, model.FieldError{Field: "period", Message: "harus year, semester, atau month"})
+	}
+
+	f.YearOfEntry = parsePositiveQuery(c, "year_of_entry", 0, &errs)
+	f.From = parseListDate(c, "from", false, &errs)
+	f.To = parseListDate(c, "to", true, &errs)
+	checkDateRange(f.From, f.To, "to", &errs)
+
+	return f, errs
+}
+
+func (s *ReportService) StatisticsService(c *fiber.Ctx) error {
+	filter, fieldErrs := parseReportFilter(c)
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Parameter query tidak valid", Data: fieldErrs})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+
+	rows, missing, err := s.loadReportRows(ctx, filter, reportProjection)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menyusun statistik"})
+	}
+
+	byType, byLevel, byProgram, byEntry, byPeriod, byStatus :=
+		statGrouper{}, statGrouper{}, statGrouper{}, statGrouper{}, statGrouper{}, statGrouper{}
+	stats := model.AchievementStatistics{
+		Period:           filter.Period,
+		Statuses:         filter.Statuses,
+		Total:            model.StatBucket{Key: "total"},
+		MissingDocuments: missing,
+		GeneratedAt:      time.Now(),
+	}
+
+	for _, r := range rows {
+		points := countedPoints(r.Key.ReferenceID, r.Key.Status, r.Doc)
+		stats.Total.Count++
+		stats.Total.Points += points
+
+		byType.add(r.Doc.AchievementType, points)
+		if r.Doc.AchievementType == model.AchievementTypeCompetition {
+			byLevel.add(r.Doc.Details.CompetitionLevel, points)
+		}
+		byProgram.add(r.Key.StudyProgram, points)
+		entry := ""
+		if r.Key.YearOfEntry > 0 {
+			entry = strconv.Itoa(r.Key.YearOfEntry)
+		}
+		byEntry.add(entry, points)
+		byPeriod.add(periodKey(achievementDate(r.Doc), filter.Period), points)
+		byStatus.add(r.Key.Status, points)
+	}
+
+	stats.ByAchievementType = byType.sorted(false)
+	stats.ByCompetitionLevel = byLevel.sorted(false)
+	stats.ByStudyProgram = byProgram.sorted(false)
+	stats.ByYearOfEntry = byEntry.sorted(true)
+	stats.ByPeriod = byPeriod.sorted(true)
+	stats.ByStatus = byStatus.sorted(false)
+
+	return c.JSON(model.APIResponse{Status: "success", Data: stats})
+}
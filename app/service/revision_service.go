@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"go-fiber/app/model"
+	"go-fiber/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Field yang selalu berubah di setiap snapshot dan tidak relevan untuk diff.
+var revisionDiffIgnored = map[string]bool{
+	"id":        true,
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+func revisionOnEdit() bool {
+	return utils.GetEnvBool("ACHIEVEMENT_REVISION_ON_EDIT", false)
+}
+
+// loadReadableReference mengambil reference dan menerapkan aturan akses baca
+// yang sama dengan GetAchievementDetailService.
+func (s *AchievementService) loadReadableReference(c *fiber.Ctx) (*model.AchievementDetailResponse, *reviewError) {
+	role := getUserRole(c)
+	userID := getUserID(c)
+
+	ref, err := s.PGRepo.GetReferenceDetail(c.Params("id"))
+	if err != nil {
+		return nil, &reviewError{Status: 404, Message: "Reference tidak ditemukan"}
+	}
+	if role == "Mahasiswa" && ref.StudentID != userID {
+		return nil, &reviewError{Status: 403, Message: "Tidak boleh melihat data milik orang lain"}
+	}
+	if role == "Dosen Wali" && ref.AdvisorID != userID {
+		return nil, &reviewError{Status: 403, Message: "Anda bukan dosen wali mahasiswa ini"}
+	}
+	if ref.ReferenceStatus == "deleted" && role != "Admin" {
+		return nil, &reviewError{Status: 403, Message: "Data telah dihapus"}
+	}
+	return ref, nil
+}
+
+// snapshotRevision menyimpan salinan dokumen MongoDB saat ini sebagai revisi baru.
+func (s *AchievementService) snapshotRevision(ctx context.Context, refID, mongoHex, reason, actorID string) error {
+	ach, err := s.Mongo.FindByHexID(ctx, mongoHex)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Revisions.Create(ctx, model.AchievementRevision{
+		AchievementID: mongoHex,
+		ReferenceID:   refID,
+		Reason:        reason,
+		Snapshot:      ach,
+		CreatedBy:     actorID,
+	})
+	return err
+}
+
+func (s *AchievementService) ListRevisionsService(c *fiber.Ctx) error {
+	ref, rerr := s.loadReadableReference(c)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	list, err := s.Revisions.List(context.Background(), ref.MongoID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil revisi"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: list})
+}
+
+func (s *AchievementService) GetRevisionService(c *fiber.Ctx) error {
+	ref, rerr := s.loadReadableReference(c)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	revision, err := strconv.Atoi(c.Params("rev"))
+	if err != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Nomor revisi tidak valid"})
+	}
+
+	rev, err := s.Revisions.Get(context.Background(), ref.MongoID, revision)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Revisi tidak ditemukan"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: rev})
+}
+
+func (s *AchievementService) DiffRevisionsService(c *fiber.Ctx) error {
+	ref, rerr := s.loadReadableReference(c)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Query from dan to wajib berupa nomor revisi"})
+	}
+
+	ctx := context.Background()
+	fromRev, err := s.Revisions.Get(ctx, ref.MongoID, from)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: fmt.Sprintf("Revisi %d tidak ditemukan", from)})
+	}
+	toRev, err := s.Revisions.Get(ctx, ref.MongoID, to)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: fmt.Sprintf("Revisi %d tidak ditemukan", to)})
+	}
+
+	changes, err := diffDocuments(fromRev.Snapshot, toRev.Snapshot)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membandingkan revisi"})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   model.RevisionDiffResponse{From: from, To: to, Changes: changes},
+	})
+}
+
+// diffDocuments membandingkan dua dokumen per field (path bertitik, indeks
+// array dalam kurung siku) berdasarkan representasi JSON-nya.
+func diffDocuments(a, b interface{}) ([]model.FieldChange, error) {
+	flatA, err := flattenJSON(a)
+	if err != nil {
+		return nil, err
+	}
+	flatB, err := flattenJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for k := range flatA {
+		keys[k] = true
+	}
+	for k := range flatB {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changes := []model.FieldChange{}
+	for _, k := range sorted {
+		va, inA := flatA[k]
+		vb, inB := flatB[k]
+		switch {
+		case inA && !inB:
+			changes = append(changes, model.FieldChange{Field: k, Change: "removed", From: va})
+		case !inA && inB:
+			changes = append(changes, model.FieldChange{Field: k, Change: "added", To: vb})
+		case !reflect.DeepEqual(va, vb):
+			changes = append(changes, model.FieldChange{Field: k, Change: "modified", From: va, To: vb})
+		}
+	}
+	return changes, nil
+}
+
+func flattenJSON(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+
+	out := map[string]interface{}{}
+	flattenInto(out, "", generic)
+	return out, nil
+}
+
+func flattenInto(out map[string]interface{}, prefix string, v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if prefix == "" && revisionDiffIgnored[k] {
+				continue
+			}
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			flattenInto(out, path, child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			flattenInto(out, fmt.Sprintf("%s[%d]", prefix, i), child)
+		}
+	default:
+		if prefix != "" && v != nil {
+			out[prefix] = v
+		}
+	}
+}
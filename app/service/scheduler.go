@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"go-fiber/app/repository"
+	"go-fiber/middleware"
+	"go-fiber/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StartSchedulers menjalankan job latar belakang selama aplikasi hidup.
+func StartSchedulers(db *sql.DB, mongoDB *mongo.Database) {
+	svc := NewAchievementService(db, mongoDB)
+
+	outboxInterval := time.Duration(utils.GetEnvInt("OUTBOX_POLL_INTERVAL_SECONDS", 30)) * time.Second
+	go runEvery("outbox-worker", outboxInterval, func(ctx context.Context) {
+		done, failed, err := svc.ProcessOutbox(ctx)
+		if err != nil {
+			log.Printf("Outbox worker failed: %v", err)
+		}
+		if done > 0 || failed > 0 {
+			log.Printf("Outbox worker: %d events completed, %d given up", done, failed)
+		}
+		if _, err := svc.Outbox.DeleteProcessedBefore(7); err != nil {
+			log.Printf("Outbox cleanup failed: %v", err)
+		}
+	})
+
+	idempotencyRepo := repository.NewIdempotencyRepo(db)
+	go runEvery("idempotency-cleanup", time.Hour, func(ctx context.Context) {
+		if _, err := idempotencyRepo.DeleteExpired(middleware.IdempotencyRetentionHours()); err != nil {
+			log.Printf("Idempotency cleanup failed: %v", err)
+		}
+	})
+
+	purgeInterval := time.Duration(utils.GetEnvInt("ACHIEVEMENT_PURGE_INTERVAL_HOURS", 24)) * time.Hour
+	go runEvery("purge-deleted-achievements", purgeInterval, func(ctx context.Context) {
+		results, err := svc.PurgeDeletedAchievements(ctx, purgeAfterDays(), 500)
+		if err != nil {
+			log.Printf("Purge job failed: %v", err)
+			return
+		}
+		purged := 0
+		for _, r := range results {
+			if r.Status == "purged" {
+				purged++
+			} else {
+				log.Printf("Purge %s failed: %s", r.ReferenceID, r.Error)
+			}
+		}
+		if len(results) > 0 {
+			log.Printf("Purge job: %d/%d achievements purged", purged, len(results))
+		}
+	})
+
+	slaInterval := time.Duration(utils.GetEnvInt("ACHIEVEMENT_SLA_CHECK_INTERVAL_HOURS", 1)) * time.Hour
+	go runEvery("verification-sla", slaInterval, func(ctx context.Context) {
+		reminded, escalated, err := svc.CheckSLA(ctx)
+		if err != nil {
+			log.Printf("SLA job failed: %v", err)
+		}
+		if reminded > 0 || escalated > 0 {
+			log.Printf("SLA job: %d reminders sent, %d submissions escalated", reminded, escalated)
+		}
+	})
+
+	expiryInterval := time.Duration(utils.GetEnvInt("CERTIFICATION_EXPIRY_INTERVAL_HOURS", 24)) * time.Hour
+	go runEvery("certification-expiry", expiryInterval, func(ctx context.Context) {
+		warned, expired, err := svc.CheckCertificationExpiry(ctx)
+		if err != nil {
+			log.Printf("Certification expiry job failed: %v", err)
+		}
+		if warned > 0 || expired > 0 {
+			log.Printf("Certification expiry job: %d warned, %d expired", warned, expired)
+		}
+	})
+}
+
+func runEvery(name string, interval time.Duration, job func(ctx context.Context)) {
+	if interval <= 0 {
+		log.Printf("Scheduler %s disabled", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Scheduler %s panic: %v", name, r)
+				}
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			defer cancel()
+			job(ctx)
+		}()
+		<-ticker.C
+	}
+}
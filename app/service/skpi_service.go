@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+	"go-fiber/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const skpiNumberPrefix = "SKPI"
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+func formatTanggal(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
+
+func institutionName() string {
+	if v := os.Getenv("INSTITUTION_NAME"); v != "" {
+		return v
+	}
+	return "Universitas"
+}
+
+// achievementSummaryLine merangkum detail utama prestasi sesuai tipenya.
+func achievementSummaryLine(doc model.Achievement) string {
+	d := doc.Details
+	var parts []string
+	switch doc.AchievementType {
+	case model.AchievementTypeCompetition:
+		parts = append(parts, d.CompetitionName)
+		if d.Rank > 0 {
+			parts = append(parts, "Peringkat "+strconv.Itoa(d.Rank))
+		}
+		parts = append(parts, d.MedalType)
+	case model.AchievementTypePublication:
+		parts = append(parts, d.PublicationTitle, d.Publisher)
+	case model.AchievementTypeOrganization:
+		parts = append(parts, d.OrganizationName, d.Position)
+	case model.AchievementTypeCertification:
+		parts = append(parts, d.CertificationName, d.IssuedBy)
+	}
+	if d.Organizer != "" {
+		parts = append(parts, d.Organizer)
+	}
+
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " - ")
+}
+
+type skpiRow struct {
+	Entry model.SKPIEntry
+	Doc   model.Achievement
+}
+
+func renderSKPI(number string, student *model.StudentDetailResponse, rows []skpiRow, typeLabels map[string]string, totalPoints int, issuedAt time.Time) ([]byte, error) {
+	pdf := utils.NewPDF()
+	pdf.Title = "SKPI " + student.FullName
+	pdf.Author = institutionName()
+	pdf.Subject = "Surat Keterangan Pendamping Ijazah - Lampiran Prestasi"
+	pdf.SetFooter("No. " + number + " - diterbitkan " + formatTanggal(issuedAt))
+
+	pdf.SetFont(true, 13)
+	pdf.ParagraphAligned(strings.ToUpper(institutionName()), "center")
+	pdf.SetFont(true, 12)
+	pdf.ParagraphAligned("SURAT KETERANGAN PENDAMPING IJAZAH (SKPI)", "center")
+	pdf.SetFont(false, 10)
+	pdf.ParagraphAligned("Lampiran Prestasi dan Penghargaan Mahasiswa", "center")
+	pdf.ParagraphAligned("Nomor: "+number, "center")
+	pdf.Rule()
+
+	advisor := "-"
+	if student.AdvisorName != nil && *student.AdvisorName != "" {
+		advisor = *student.AdvisorName
+	}
+	entryYear := "-"
+	if student.YearOfEntry > 0 {
+		entryYear = strconv.Itoa(student.YearOfEntry)
+	}
+	const labelWidth = 110
+	pdf.KeyValue("Nama", student.FullName, labelWidth)
+	pdf.KeyValue("NIM", student.StudentID, labelWidth)
+	pdf.KeyValue("Program Studi", student.StudyProgram, labelWidth)
+	pdf.KeyValue("Tahun Masuk", entryYear, labelWidth)
+	pdf.KeyValue("Dosen Wali", advisor, labelWidth)
+	pdf.Space(10)
+
+	pdf.SetFont(true, 10)
+	pdf.Paragraph("Daftar Prestasi Terverifikasi")
+	pdf.Space(4)
+
+	pdf.SetFont(false, 9)
+	widths := []float64{25, 190, 90, 130, 60}
+	pdf.TableHeader(widths, []string{"No", "Prestasi", "Kategori", "Verifikasi", "Poin"})
+	for i, r := range rows {
+		title := r.Doc.Title
+		if line := achievementSummaryLine(r.Doc); line != "" {
+			title += "\n" + line
+		}
+
+		category := typeLabels[r.Doc.AchievementType]
+		if category == "" {
+			category = r.Doc.AchievementType
+		}
+		if r.Doc.Details.CompetitionLevel != "" {
+			category += "\nTingkat " + r.Doc.Details.CompetitionLevel
+		}
+
+		verification := formatTanggal(r.Entry.VerifiedAt)
+		if r.Entry.VerifierName != "" {
+			verification += "\noleh " + r.Entry.VerifierName
+		}
+
+		points := strconv.Itoa(countedPoints(r.Entry.ReferenceID, "verified", r.Doc))
+		if r.Doc.PointsExcluded {
+			points += "\n(sertifikasi kedaluwarsa)"
+		}
+
+		pdf.TableRow(widths, []string{strconv.Itoa(i + 1), title, category, verification, points})
+	}
+	pdf.EndTable()
+	pdf.Space(6)
+
+	pdf.SetFont(true, 10)
+	pdf.Paragraph(fmt.Sprintf("Jumlah prestasi: %d    Total poin: %d", len(rows), totalPoints))
+	pdf.Space(10)
+
+	pdf.SetFont(false, 10)
+	pdf.Paragraph("Dokumen ini merupakan lampiran Surat Keterangan Pendamping Ijazah yang memuat prestasi mahasiswa " +
+		"yang telah diverifikasi oleh dosen wali melalui Sistem Pelaporan Prestasi Mahasiswa.")
+	pdf.Space(16)
+	pdf.ParagraphAligned("Diterbitkan pada "+formatTanggal(issuedAt), "right")
+
+	return pdf.Output()
+}
+
+// GenerateSKPIService menerbitkan PDF SKPI berisi seluruh prestasi verified
+// mahasiswa. Setiap penerbitan mendapat nomor dokumen baru yang dicatat.
+func (s *ReportService) GenerateSKPIService(c *fiber.Ctx) error {
+	role := getUserRole(c)
+	userID := getUserID(c)
+
+	student, err := repository.GetStudentByID(s.PG, c.Params("id"))
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Mahasiswa tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data mahasiswa"})
+	}
+	if msg := studentAccessError(role, userID, student); msg != "" {
+		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: msg})
+	}
+
+	entries, err := s.SKPI.ListVerifiedEntries(student.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data prestasi"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.MongoID
+	}
+	docs, err := s.Mongo.FindByHexIDs(ctx, ids, nil)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data prestasi"})
+	}
+
+	var rows []skpiRow
+	var refIDs []string
+	totalPoints := 0
+	for _, e := range entries {
+		doc, ok := docs[e.MongoID]
+		if !ok {
+			log.Printf("SKPI for student %s: skipping reference %s with missing document %s", student.ID, e.ReferenceID, e.MongoID)
+			continue
+		}
+		rows = append(rows, skpiRow{Entry: e, Doc: doc})
+		refIDs = append(refIDs, e.ReferenceID)
+		totalPoints += countedPoints(e.ReferenceID, "verified", doc)
+	}
+	if len(rows) == 0 {
+		return c.Status(422).JSON(model.APIResponse{Status: "error", Error: "Mahasiswa belum memiliki prestasi terverifikasi"})
+	}
+
+	typeLabels := map[string]string{}
+	if types, err := s.Types.ListActive(ctx); err == nil {
+		for _, t := range types {
+			typeLabels[t.Code] = t.Label
+		}
+	}
+
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menerbitkan SKPI"})
+	}
+	defer tx.Rollback()
+
+	issuedAt := time.Now()
+	number, err := s.SKPI.NextDocumentNumber(tx, skpiNumberPrefix, issuedAt.Year())
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuat nomor dokumen"})
+	}
+
+	doc := model.SKPIDocument{
+		DocumentNumber:   number,
+		StudentID:        student.ID,
+		GeneratedBy:      userID,
+		ReferenceIDs:     refIDs,
+		AchievementCount: len(rows),
+		TotalPoints:      totalPoints,
+	}
+	if err := s.SKPI.InsertDocument(tx, &doc); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menerbitkan SKPI"})
+	}
+
+	pdf, err := renderSKPI(number, student, rows, typeLabels, totalPoints, issuedAt)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal membuat PDF"})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menerbitkan SKPI"})
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="SKPI-%s.pdf"`, student.StudentID))
+	c.Set("X-Document-Number", number)
+	return c.Send(pdf)
+}
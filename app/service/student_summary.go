@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// studentRank menghitung peringkat mahasiswa di program studinya berdasarkan
+// total poin verified. Mahasiswa dengan poin sama mendapat peringkat sama.
+func (s *ReportService) studentRank(ctx context.Context, studentID, studyProgram string) (*model.StudentRank, error) {
+	rows, _, err := s.loadReportRows(ctx, model.ReportFilter{
+		Statuses:     []string{"verified"},
+		StudyProgram: studyProgram,
+	}, bson.M{"points": 1, "referencePoints": 1, "pointsExcluded": 1})
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]int{}
+	for _, r := range rows {
+		totals[r.Key.StudentID] += countedPoints(r.Key.ReferenceID, r.Key.Status, r.Doc)
+	}
+
+	outOf, err := repository.CountStudentsInProgram(s.PG, studyProgram)
+	if err != nil {
+		return nil, err
+	}
+
+	own := totals[studentID]
+	rank := &model.StudentRank{StudyProgram: studyProgram, Position: 1, OutOf: outOf, Points: own}
+	for id, points := range totals {
+		if id != studentID && points > own {
+			rank.Position++
+		}
+	}
+	return rank, nil
+}
+
+func (s *ReportService) StudentSummaryService(c *fiber.Ctx) error {
+	role := getUserRole(c)
+	userID := getUserID(c)
+
+	student, err := repository.GetStudentByID(s.PG, c.Params("id"))
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Mahasiswa tidak ditemukan"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data mahasiswa"})
+	}
+	if msg := studentAccessError(role, userID, student); msg != "" {
+		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: msg})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+
+	refs, err := s.Refs.ListByStudentID(student.ID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data prestasi"})
+	}
+	missing, err := hydrateReferences(ctx, s.Mongo, refs)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil data prestasi"})
+	}
+
+	summary := model.StudentAchievementSummary{
+		Student:          *student,
+		StatusCounts:     map[string]int{"draft": 0, "submitted": 0, "verified": 0, "rejected": 0},
+		MissingDocuments: missing,
+		GeneratedAt:      time.Now(),
+	}
+
+	byType, byLevel, bySemester := statGrouper{}, statGrouper{}, statGrouper{}
+	for _, ref := range refs {
+		summary.StatusCounts[ref.ReferenceStatus]++
+		if ref.ReferenceStatus != "verified" || ref.DocumentMissing {
+			continue
+		}
+
+		doc := ref.Achievement
+		points := countedPoints(ref.ReferenceID, ref.ReferenceStatus, doc)
+		summary.VerifiedCount++
+		summary.TotalPoints += points
+
+		byType.add(doc.AchievementType, points)
+		if doc.AchievementType == model.AchievementTypeCompetition {
+			byLevel.add(doc.Details.CompetitionLevel, points)
+		}
+		bySemester.add(academicSemester(achievementDate(doc)), points)
+	}
+	summary.ByAchievementType = byType.sorted(false)
+	summary.ByCompetitionLevel = byLevel.sorted(false)
+	summary.PointsPerSemester = bySemester.sorted(true)
+
+	if student.StudyProgram != "" {
+		rank, err := s.studentRank(ctx, student.ID, student.StudyProgram)
+		if err != nil {
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menghitung peringkat"})
+		}
+		summary.Rank = rank
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: summary})
+}
@@ -6,12 +6,25 @@ import (
 	"go-fiber/app/model"
 	"go-fiber/app/repository"
 	"go.mongodb.org/mongo-driver/mongo"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func GetAllStudentsService(c *fiber.Ctx, db *sql.DB) error {
-	students, err := repository.GetAllStudents(db)
+	filter, fieldErrs := parseDirectoryQuery(c, repository.StudentSortFields(), "created_at")
+	filter.StudyProgram = strings.TrimSpace(c.Query("study_program"))
+	filter.AdvisorID = strings.TrimSpace(c.Query("advisor_id"))
+	filter.YearOfEntry = parsePositiveQuery(c, "year_of_entry", 0, &fieldErrs)
+	if len(fieldErrs) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Parameter query tidak valid",
+			Data:   fieldErrs,
+		})
+	}
+
+	students, total, err := repository.GetAllStudents(db, filter)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(model.APIResponse{
 			Status: "error",
@@ -22,6 +35,7 @@ func GetAllStudentsService(c *fiber.Ctx, db *sql.DB) error {
 	return c.JSON(model.APIResponse{
 		Status: "success",
 		Data:   students,
+		Meta:   model.NewPageMeta(filter.Page, filter.Limit, total),
 	})
 }
 
@@ -58,23 +72,13 @@ func GetStudentAchievementsService(c *fiber.Ctx, db *sql.DB, mongoDB *mongo.Data
 	}
 
 	// === 2. Validasi akses ===
-	if role == "Student" && userID != studentIDParam {
+	if msg := studentAccessError(role, userID, student); msg != "" {
 		return c.Status(403).JSON(model.APIResponse{
 			Status: "error",
-			Error:  "Tidak boleh melihat prestasi mahasiswa lain",
+			Error:  msg,
 		})
 	}
 
-	if role == "Dosen Wali" {
-		advisor, _ := student.AdvisorID, student.AdvisorID
-		if advisor == nil || *advisor != userID {
-			return c.Status(403).JSON(model.APIResponse{
-				Status: "error",
-				Error:  "Anda bukan dosen wali mahasiswa ini",
-			})
-		}
-	}
-
 	// === 3. Ambil list achievement reference ===
 	refRepo := repository.NewAchievementRefRepo(db)
 	mongoRepo := repository.NewAchievementMongoRepo(mongoDB)
@@ -88,17 +92,19 @@ func GetStudentAchievementsService(c *fiber.Ctx, db *sql.DB, mongoDB *mongo.Data
 	}
 
 	// === 4. Inject data MongoDB ke tiap ref ===
-	for i := range refs {
-		doc, err := mongoRepo.FindByHexID(context.Background(), refs[i].MongoID)
-		if err == nil {
-			refs[i].Achievement = *doc
-		}
+	missing, err := hydrateReferences(context.Background(), mongoRepo, refs)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Gagal mengambil data prestasi",
+		})
 	}
 
 	// === 5. Format response sesuai SRS ===
 	result := model.StudentAchievementsResponse{
-		Student:      *student,
-		Achievements: refs,
+		Student:          *student,
+		Achievements:     refs,
+		MissingDocuments: missing,
 	}
 
 	return c.JSON(model.APIResponse{
@@ -107,6 +113,22 @@ func GetStudentAchievementsService(c *fiber.Ctx, db *sql.DB, mongoDB *mongo.Data
 	})
 }
 
+// studentAccessError memeriksa apakah user boleh melihat data prestasi
+// mahasiswa: mahasiswa hanya dirinya sendiri, dosen wali hanya bimbingannya.
+func studentAccessError(role, userID string, student *model.StudentDetailResponse) string {
+	switch role {
+	case "Mahasiswa":
+		if userID != student.ID {
+			return "Tidak boleh melihat prestasi mahasiswa lain"
+		}
+	case "Dosen Wali":
+		if student.AdvisorID == nil || *student.AdvisorID != userID {
+			return "Anda bukan dosen wali mahasiswa ini"
+		}
+	}
+	return ""
+}
+
 func UpdateStudentAdvisorService(c *fiber.Ctx, db *sql.DB) error {
 	studentID := c.Params("id")
 
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// validateTeamMembers memastikan setiap anggota adalah mahasiswa yang terdaftar
+// dan bukan pemilik prestasi, lalu mengembalikan daftar tanpa duplikat.
+func (s *AchievementService) validateTeamMembers(ownerID string, ids []string) ([]string, []model.FieldError) {
+	seen := map[string]bool{}
+	var out []string
+	var fieldErrs []model.FieldError
+
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if id == ownerID {
+			fieldErrs = append(fieldErrs, model.FieldError{Field: "team_member_ids", Message: "pemilik prestasi tidak perlu diundang"})
+			continue
+		}
+		if _, err := repository.GetStudentByID(s.PG, id); err != nil {
+			fieldErrs = append(fieldErrs, model.FieldError{Field: "team_member_ids", Message: "mahasiswa " + id + " tidak ditemukan"})
+			continue
+		}
+		out = append(out, id)
+	}
+
+	return out, fieldErrs
+}
+
+func (s *AchievementService) InviteTeamMembersService(c *fiber.Ctx) error {
+	userID := getUserID(c)
+	refID := c.Params("id")
+
+	ref, err := s.PGRepo.GetReference(refID)
+	if err != nil || ref.StudentID != userID {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Reference tidak ditemukan"})
+	}
+
+	if ref.ReferenceStatus == "verified" || ref.ReferenceStatus == "deleted" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Anggota tidak bisa ditambahkan pada prestasi ini"})
+	}
+
+	ach, rerr := s.findDocument(ref.MongoID)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+	if ach.StudentID != userID {
+		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: "Hanya pemilik prestasi tim yang dapat mengundang anggota"})
+	}
+	if rerr := s.ensureDocumentUnlocked(ref.MongoID); rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	var req model.InviteTeamMembersRequest
+	if err := c.BodyParser(&req); err != nil || len(req.StudentIDs) == 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "student_ids wajib diisi"})
+	}
+
+	members, fieldErrs := s.validateTeamMembers(userID, req.StudentIDs)
+	if len(fieldErrs) > 0 {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Validasi gagal", Data: fieldErrs})
+	}
+
+	tx, err := s.PG.Begin()
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengundang anggota tim"})
+	}
+	for _, memberID := range members {
+		if err := s.Team.Invite(tx, ref.MongoID, memberID, userID); err != nil {
+			tx.Rollback()
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengundang anggota tim"})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengundang anggota tim"})
+	}
+
+	if !ach.IsTeam {
+		update := bson.M{"isTeam": true}
+		if ach.TeamVerification == "" {
+			update["teamVerification"] = model.TeamVerificationPerMember
+		}
+		if err := s.Mongo.UpdateByHexID(context.Background(), ref.MongoID, update); err != nil {
+			return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal update MongoDB"})
+		}
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Message: "Undangan anggota tim terkirim"})
+}
+
+func (s *AchievementService) GetTeamService(c *fiber.Ctx) error {
+	refID := c.Params("id")
+	role := getUserRole(c)
+	userID := getUserID(c)
+
+	ref, err := s.PGRepo.GetReferenceDetail(refID)
+	if err != nil {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Reference tidak ditemukan"})
+	}
+
+	if role == "Mahasiswa" && ref.StudentID != userID {
+		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: "Tidak boleh melihat data milik orang lain"})
+	}
+	if role == "Dosen Wali" && ref.AdvisorID != userID {
+		return c.Status(403).JSON(model.APIResponse{Status: "error", Error: "Anda bukan dosen wali mahasiswa ini"})
+	}
+
+	ach, rerr := s.findDocument(ref.MongoID)
+	if rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	members, err := s.Team.ListByAchievement(ref.MongoID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil anggota tim"})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data: fiber.Map{
+			"owner_id":          ach.StudentID,
+			"is_team":           ach.IsTeam,
+			"team_verification": ach.TeamVerification,
+			"members":           members,
+		},
+	})
+}
+
+func (s *AchievementService) ListTeamInvitationsService(c *fiber.Ctx) error {
+	userID := getUserID(c)
+
+	invites, err := s.Team.ListPendingForStudent(userID)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil undangan tim"})
+	}
+
+	ids := make([]string, len(invites))
+	for i, inv := range invites {
+		ids[i] = inv.MongoID
+	}
+	docs, err := findTitles(context.Background(), s.Mongo, ids)
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal mengambil undangan tim"})
+	}
+
+	out := make([]model.TeamInvitationResponse, 0, len(invites))
+	for _, inv := range invites {
+		item := model.TeamInvitationResponse{TeamMember: inv}
+		if doc, ok := docs[inv.MongoID]; ok {
+			item.Title = doc.Title
+			item.AchievementType = doc.AchievementType
+		}
+		out = append(out, item)
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Data: out})
+}
+
+func (s *AchievementService) ConfirmTeamInvitationService(c *fiber.Ctx) error {
+	userID := getUserID(c)
+
+	inv, err := s.Team.GetByID(c.Params("inviteId"))
+	if err != nil || inv.StudentID != userID {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Undangan tidak ditemukan"})
+	}
+	if inv.Status != "invited" {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Undangan sudah direspons"})
+	}
+	if _, rerr := s.findDocument(inv.MongoID); rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+	if rerr := s.ensureDocumentUnlocked(inv.MongoID); rerr != nil {
+		return c.Status(rerr.Status).JSON(model.APIResponse{Status: "error", Error: rerr.Message})
+	}
+
+	refID, err := s.Team.Confirm(inv.ID, userID, inv.MongoID)
+	if err == sql.ErrNoRows {
+		return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Undangan sudah direspons"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal konfirmasi undangan"})
+	}
+
+	if err := s.Mongo.AddTeamMember(context.Background(), inv.MongoID, userID); err != nil {
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal update anggota tim di MongoDB"})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "Undangan dikonfirmasi",
+		Data: fiber.Map{
+			"reference_id": refID,
+			"mongo_id":     inv.MongoID,
+		},
+	})
+}
+
+func (s *AchievementService) DeclineTeamInvitationService(c *fiber.Ctx) error {
+	userID := getUserID(c)
+
+	inv, err := s.Team.GetByID(c.Params("inviteId"))
+	if err != nil || inv.StudentID != userID {
+		return c.Status(404).JSON(model.APIResponse{Status: "error", Error: "Undangan tidak ditemukan"})
+	}
+
+	if err := s.Team.Decline(inv.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(400).JSON(model.APIResponse{Status: "error", Error: "Undangan sudah direspons"})
+		}
+		return c.Status(500).JSON(model.APIResponse{Status: "error", Error: "Gagal menolak undangan"})
+	}
+
+	return c.JSON(model.APIResponse{Status: "success", Message: "Undangan ditolak"})
+}
@@ -4,13 +4,24 @@ import (
 	"database/sql"
 	"go-fiber/app/model"
 	"go-fiber/app/repository"
+	"strings"
 	"go-fiber/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func GetAllUsersService(c *fiber.Ctx, db *sql.DB) error {
-	users, err := repository.GetAllUsers(db)
+	filter, fieldErrs := parseDirectoryQuery(c, repository.UserSortFields(), "created_at")
+	filter.Role = strings.TrimSpace(c.Query("role"))
+	if len(fieldErrs) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Parameter query tidak valid",
+			Data:   fieldErrs,
+		})
+	}
+
+	users, total, err := repository.GetAllUsers(db, filter)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(model.APIResponse{
 			Status: "error",
@@ -21,6 +32,7 @@ func GetAllUsersService(c *fiber.Ctx, db *sql.DB) error {
 	return c.JSON(model.APIResponse{
 		Status: "success",
 		Data:   users,
+		Meta:   model.NewPageMeta(filter.Page, filter.Limit, total),
 	})
 }
 
@@ -138,4 +150,58 @@ func AssignRoleService(c *fiber.Ctx, db *sql.DB) error {
 		Status:  "success",
 		Message: "Role berhasil diperbarui",
 	})
-}
\ No newline at end of file
+}
+// GetStudyProgramsService menampilkan program studi yang ditangani admin.
+func GetStudyProgramsService(c *fiber.Ctx, db *sql.DB) error {
+	programs, err := repository.GetAdminStudyPrograms(db, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Gagal mengambil program studi admin",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status: "success",
+		Data:   fiber.Map{"study_programs": programs},
+	})
+}
+
+// AssignStudyProgramsService menugaskan admin ke program studi. Eskalasi SLA
+// dan antrean eskalasinya dibatasi ke program studi tersebut.
+func AssignStudyProgramsService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.AssignStudyProgramsRequest
+	if err := c.BodyParser(&req); err != nil || req.StudyPrograms == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Field study_programs wajib diisi",
+		})
+	}
+
+	programs := []string{}
+	for _, p := range req.StudyPrograms {
+		if p = strings.TrimSpace(p); p != "" {
+			programs = append(programs, p)
+		}
+	}
+
+	err := repository.SetAdminStudyPrograms(db, c.Params("id"), programs)
+	if err == sql.ErrNoRows {
+		return c.Status(fiber.StatusBadRequest).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Program studi hanya bisa ditugaskan ke admin",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.APIResponse{
+			Status: "error",
+			Error:  "Gagal menyimpan program studi admin",
+		})
+	}
+
+	return c.JSON(model.APIResponse{
+		Status:  "success",
+		Message: "Program studi admin berhasil diperbarui",
+		Data:    fiber.Map{"study_programs": programs},
+	})
+}
@@ -79,6 +79,123 @@ func RunMigrations(db *sql.DB) error {
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
 
+		// Create achievement_team_members table
+		`CREATE TABLE IF NOT EXISTS achievement_team_members (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			mongo_achievement_id VARCHAR(24) NOT NULL,
+			student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+			invited_by UUID REFERENCES students(id) ON DELETE SET NULL,
+			status VARCHAR(20) NOT NULL CHECK (status IN ('invited', 'confirmed', 'declined')),
+			reference_id UUID REFERENCES achievement_references(id) ON DELETE SET NULL,
+			invited_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			responded_at TIMESTAMP,
+			UNIQUE (mongo_achievement_id, student_id)
+		)`,
+
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS review_started_at TIMESTAMP`,
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS review_started_by UUID REFERENCES users(id) ON DELETE SET NULL`,
+
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`,
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS deleted_from_status VARCHAR(20)`,
+
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS sla_reminded_at TIMESTAMP`,
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP`,
+
+		// Versi reference naik otomatis setiap status berubah (dipakai untuk ETag)
+		`ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1`,
+		`CREATE OR REPLACE FUNCTION bump_achievement_reference_version() RETURNS TRIGGER AS $$
+		BEGIN
+			IF NEW.status IS DISTINCT FROM OLD.status THEN
+				NEW.version := OLD.version + 1;
+			END IF;
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS trg_achievement_reference_version ON achievement_references`,
+		`CREATE TRIGGER trg_achievement_reference_version
+			BEFORE UPDATE ON achievement_references
+			FOR EACH ROW EXECUTE FUNCTION bump_achievement_reference_version()`,
+
+		// Create achievement_status_history table
+		`CREATE TABLE IF NOT EXISTS achievement_status_history (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			reference_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+			from_status VARCHAR(20),
+			to_status VARCHAR(20) NOT NULL,
+			actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			note TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create notifications table
+		`CREATE TABLE IF NOT EXISTS notifications (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			type VARCHAR(50) NOT NULL,
+			title VARCHAR(200) NOT NULL,
+			message TEXT,
+			reference_id UUID REFERENCES achievement_references(id) ON DELETE SET NULL,
+			is_read BOOLEAN DEFAULT false,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Create idempotency_keys table
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			idem_key VARCHAR(255) NOT NULL,
+			method VARCHAR(10) NOT NULL,
+			path VARCHAR(255) NOT NULL,
+			request_hash CHAR(64) NOT NULL,
+			status_code INT,
+			content_type VARCHAR(100),
+			response_body BYTEA,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, idem_key)
+		)`,
+		`ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS response_headers JSONB`,
+
+		// Create outbox_events table
+		`CREATE TABLE IF NOT EXISTS outbox_events (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			event_type VARCHAR(50) NOT NULL,
+			aggregate_id VARCHAR(64) NOT NULL,
+			payload JSONB NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'compensated', 'failed')),
+			attempts INT NOT NULL DEFAULT 0,
+			last_error TEXT,
+			available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			processed_at TIMESTAMP
+		)`,
+
+		`ALTER TABLE students ADD COLUMN IF NOT EXISTS leaderboard_opt_out BOOLEAN NOT NULL DEFAULT false`,
+
+		// Program studi yang ditangani admin; eskalasi SLA dirutekan ke sini
+		`CREATE TABLE IF NOT EXISTS admin_study_programs (
+			admin_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			study_program VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (admin_id, study_program)
+		)`,
+
+		// Create document_sequences table
+		`CREATE TABLE IF NOT EXISTS document_sequences (
+			scope VARCHAR(50) PRIMARY KEY,
+			last_value INT NOT NULL DEFAULT 0
+		)`,
+
+		// Create skpi_documents table
+		`CREATE TABLE IF NOT EXISTS skpi_documents (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			document_number VARCHAR(50) UNIQUE NOT NULL,
+			student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+			generated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			reference_ids UUID[] NOT NULL DEFAULT '{}',
+			achievement_count INT NOT NULL,
+			total_points INT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
 		`CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id)`,
@@ -89,6 +206,15 @@ func RunMigrations(db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_student_id ON achievement_references(student_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_mongo_id ON achievement_references(mongo_achievement_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_status ON achievement_references(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_team_members_student_id ON achievement_team_members(student_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_status_history_reference_id ON achievement_status_history(reference_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, is_read)`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_deleted_at ON achievement_references(deleted_at) WHERE status = 'deleted'`,
+		`CREATE INDEX IF NOT EXISTS idx_achievement_refs_submitted_at ON achievement_references(submitted_at) WHERE status = 'submitted'`,
+		`CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(available_at) WHERE status = 'pending'`,
+		`CREATE INDEX IF NOT EXISTS idx_skpi_documents_student_id ON skpi_documents(student_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_admin_study_programs_program ON admin_study_programs(study_program)`,
 	}
 
 	for i, migration := range migrations {
@@ -108,6 +234,14 @@ func DropTables(db *sql.DB) error {
 	log.Println("Dropping all tables...")
 
 	drops := []string{
+		`DROP TABLE IF EXISTS admin_study_programs CASCADE`,
+		`DROP TABLE IF EXISTS skpi_documents CASCADE`,
+		`DROP TABLE IF EXISTS document_sequences CASCADE`,
+		`DROP TABLE IF EXISTS idempotency_keys CASCADE`,
+		`DROP TABLE IF EXISTS outbox_events CASCADE`,
+		`DROP TABLE IF EXISTS notifications CASCADE`,
+		`DROP TABLE IF EXISTS achievement_status_history CASCADE`,
+		`DROP TABLE IF EXISTS achievement_team_members CASCADE`,
 		`DROP TABLE IF EXISTS achievement_references CASCADE`,
 		`DROP TABLE IF EXISTS students CASCADE`,
 		`DROP TABLE IF EXISTS lecturers CASCADE`,
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureMongoIndexes - Create MongoDB indexes (idempotent)
+func EnsureMongoIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := map[string][]mongo.IndexModel{
+		"achievement_types": {
+			{
+				Keys:    bson.D{{Key: "code", Value: 1}, {Key: "version", Value: -1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"achievement_records": {
+			{
+				Keys: bson.D{{Key: "achievementType", Value: 1}, {Key: "details.validUntil", Value: 1}},
+			},
+			{
+				// Bahasa "none" karena isi campuran Indonesia/Inggris; stemming Inggris justru merusak hasil.
+				Keys: bson.D{
+					{Key: "title", Value: "text"},
+					{Key: "description", Value: "text"},
+					{Key: "tags", Value: "text"},
+					{Key: "details.competitionName", Value: "text"},
+					{Key: "details.publicationTitle", Value: "text"},
+					{Key: "details.organizationName", Value: "text"},
+					{Key: "details.certificationName", Value: "text"},
+				},
+				Options: options.Index().
+					SetName("achievement_text").
+					SetDefaultLanguage("none").
+					SetWeights(bson.D{
+						{Key: "title", Value: 10},
+						{Key: "tags", Value: 5},
+						{Key: "details.competitionName", Value: 5},
+						{Key: "details.publicationTitle", Value: 5},
+						{Key: "details.organizationName", Value: 5},
+						{Key: "details.certificationName", Value: 5},
+						{Key: "description", Value: 1},
+					}),
+			},
+		},
+		"achievement_revisions": {
+			{
+				Keys:    bson.D{{Key: "achievementId", Value: 1}, {Key: "revision", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+	}
+
+	for coll, models := range indexes {
+		if _, err := db.Collection(coll).Indexes().CreateMany(ctx, models); err != nil {
+			log.Printf("Failed to create indexes on %s: %v", coll, err)
+			return err
+		}
+	}
+
+	log.Println("MongoDB indexes ensured ✅")
+	return nil
+}
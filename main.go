@@ -1,10 +1,14 @@
 package main
 
 import (
-	// "context"
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"go-fiber/app/model"
+	"go-fiber/app/service"
 	"go-fiber/config"
 	"go-fiber/database"
 	"go-fiber/routes"
@@ -14,6 +18,9 @@ func main() {
 	migrate := flag.Bool("migrate", false, "Run database migrations")
 	seed := flag.Bool("seed", false, "Run database seeders")
 	reset := flag.Bool("reset", false, "Drop all tables, migrate, and seed (CAUTION: deletes all data)")
+	reconcile := flag.Bool("reconcile", false, "Compare PostgreSQL references with MongoDB documents and report inconsistencies")
+	repair := flag.Bool("repair", false, "With -reconcile: repair inconsistencies that are safe to fix")
+	dryRun := flag.Bool("dry-run", false, "With -reconcile -repair: list repairs without applying them")
 	flag.Parse()
 
 	config.LoadEnv()
@@ -27,6 +34,10 @@ func main() {
 	}
 	log.Println("MongoDB Connected")
 
+	if err := database.EnsureMongoIndexes(mongoDB); err != nil {
+		log.Fatal("Failed to ensure MongoDB indexes:", err)
+	}
+
 	if *reset {
 		log.Println("⚠️  RESETTING DATABASE - This will delete all data!")
 		
@@ -67,10 +78,26 @@ func main() {
 		return
 	}
 
+	if *reconcile {
+		log.Println("Running reconciliation...")
+		svc := service.NewAchievementService(db, mongoDB)
+		report, err := svc.Reconcile(context.Background(), model.ReconcileOptions{Repair: *repair, DryRun: *dryRun})
+		if err != nil {
+			log.Fatal("Failed to run reconciliation:", err)
+		}
+
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+		log.Printf("✅ Reconciliation completed: %d issues found", len(report.Issues))
+		return
+	}
+
 	app := config.NewApp(db)
 
 	routes.RegisterRoutes(app, db, mongoDB)
 
+	service.StartSchedulers(db, mongoDB)
+
 	port := os.Getenv("APP_PORT")
 	if port == "" {
 		port = "3000"
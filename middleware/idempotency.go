@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"go-fiber/app/model"
+	"go-fiber/app/repository"
+	"go-fiber/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// Header response yang disimpan dan diputar ulang selain Content-Type.
+var replayedHeaders = []string{
+	fiber.HeaderETag,
+	fiber.HeaderContentDisposition,
+	"X-Document-Number",
+}
+
+// IdempotencyRetentionHours adalah lama response disimpan untuk diputar ulang.
+func IdempotencyRetentionHours() int {
+	return utils.GetEnvInt("IDEMPOTENCY_RETENTION_HOURS", 24)
+}
+
+// Idempotency menyimpan response pertama untuk setiap Idempotency-Key per user
+// dan memutarnya ulang untuk retry dengan key yang sama. Request tanpa header
+// diproses seperti biasa. Harus dipasang setelah AuthRequired.
+func Idempotency(db *sql.DB) fiber.Handler {
+	repo := repository.NewIdempotencyRepo(db)
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get("Idempotency-Key")
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"error":  "Idempotency-Key terlalu panjang",
+			})
+		}
+
+		userID, _ := c.Locals("user_id").(string)
+		if userID == "" {
+			return c.Next()
+		}
+
+		// If-Match ikut di-hash: retry dengan versi berbeda adalah request berbeda.
+		sum := sha256.New()
+		sum.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+		sum.Write([]byte(c.Get(fiber.HeaderIfMatch) + "\n"))
+		if err := hashRequestBody(c, sum); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status": "error",
+				"error":  "Body request tidak valid",
+			})
+		}
+		rec := model.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Method(),
+			Path:        c.Path(),
+			RequestHash: hex.EncodeToString(sum.Sum(nil)),
+		}
+
+		reserved, err := repo.Reserve(rec, IdempotencyRetentionHours())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status": "error",
+				"error":  "Gagal memeriksa Idempotency-Key",
+			})
+		}
+
+		if !reserved {
+			existing, err := repo.Get(userID, key)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"status": "error",
+					"error":  "Gagal memeriksa Idempotency-Key",
+				})
+			}
+			if existing.RequestHash != rec.RequestHash {
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+					"status": "error",
+					"error":  "Idempotency-Key sudah dipakai untuk request yang berbeda",
+				})
+			}
+			if existing.StatusCode == nil {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"status": "error",
+					"error":  "Request dengan Idempotency-Key yang sama sedang diproses",
+				})
+			}
+
+			c.Set("Idempotent-Replayed", "true")
+			if existing.ContentType != "" {
+				c.Set(fiber.HeaderContentType, existing.ContentType)
+			}
+			for name, value := range existing.Headers {
+				c.Set(name, value)
+			}
+			return c.Status(*existing.StatusCode).Send(existing.ResponseBody)
+		}
+
+		if err := c.Next(); err != nil {
+			_ = repo.Release(userID, key)
+			return err
+		}
+
+		// Error server dan kegagalan karena versi atau konflik sementara tidak
+		// disimpan agar retry benar-benar menjalankan ulang request.
+		status := c.Response().StatusCode()
+		if !storableStatus(status) {
+			if err := repo.Release(userID, key); err != nil {
+				log.Printf("Failed to release idempotency key %s: %v", key, err)
+			}
+			return nil
+		}
+
+		contentType := string(c.Response().Header.ContentType())
+		headers := map[string]string{}
+		for _, name := range replayedHeaders {
+			if v := c.Response().Header.Peek(name); len(v) > 0 {
+				headers[name] = string(v)
+			}
+		}
+		if err := repo.Complete(userID, key, status, contentType, headers, c.Response().Body()); err != nil {
+			log.Printf("Failed to store idempotent response %s: %v", key, err)
+			// Tanpa response tersimpan key akan terus dianggap sedang diproses.
+			if err := repo.Release(userID, key); err != nil {
+				log.Printf("Failed to release idempotency key %s: %v", key, err)
+			}
+		}
+		return nil
+	}
+}
+
+// storableStatus menentukan response yang diputar ulang untuk retry. 409, 412,
+// dan 428 bergantung pada keadaan saat itu sehingga retry harus diproses ulang.
+func storableStatus(status int) bool {
+	switch status {
+	case fiber.StatusConflict, fiber.StatusPreconditionFailed, fiber.StatusPreconditionRequired:
+		return false
+	}
+	return status < fiber.StatusInternalServerError
+}
+
+// hashRequestBody menulis isi request ke hash. Body multipart di-hash dari
+// field dan file hasil parsing karena boundary berubah di setiap retry.
+func hashRequestBody(c *fiber.Ctx, sum hash.Hash) error {
+	if !strings.HasPrefix(string(c.Request().Header.ContentType()), fiber.MIMEMultipartForm) {
+		sum.Write(c.Body())
+		return nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(form.Value))
+	for name := range form.Value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range form.Value[name] {
+			fmt.Fprintf(sum, "field %q=%q\n", name, v)
+		}
+	}
+
+	names = names[:0]
+	for name := range form.File {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, fh := range form.File[name] {
+			content := sha256.New()
+			f, err := fh.Open()
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(content, f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(sum, "file %q=%q %d %x\n", name, fh.Filename, fh.Size, content.Sum(nil))
+		}
+	}
+	return nil
+}
@@ -12,24 +12,65 @@ import (
 func AchievementRoutes(app *fiber.App, db *sql.DB, mongoDB *mongo.Database) {
 	svc := service.NewAchievementService(db, mongoDB)
 	achievement := app.Group("/api/v1/achievements", middleware.AuthRequired())
+	idempotent := middleware.Idempotency(db)
 
 	achievement.Get("/", middleware.RequirePermission("achievement:read"), svc.ListAchievementsService,)
 
+	achievement.Get("/search", middleware.RequirePermission("achievement:read"), svc.SearchAchievementsService,)
+
+	achievement.Get("/facets", middleware.RequirePermission("achievement:read"), svc.FacetAchievementsService,)
+
+	achievement.Get("/export", middleware.RequirePermission("report:read"), svc.ExportAchievementsService,)
+
+	achievement.Post("/purge", middleware.RequirePermission("user:manage"), svc.BulkPurgeService,)
+
+	achievement.Post("/reconcile", middleware.RequirePermission("user:manage"), svc.ReconcileService,)
+
+	achievement.Get("/escalations", middleware.RequirePermission("user:manage"), svc.EscalationQueueService,)
+
+	achievement.Post("/review/batch", middleware.RequirePermission("achievement:verify"), idempotent, svc.BatchReviewService,)
+
+	achievement.Get("/team/invitations", middleware.RequirePermission("achievement:create"), svc.ListTeamInvitationsService,)
+
+	achievement.Post("/team/invitations/:inviteId/confirm", middleware.RequirePermission("achievement:create"), svc.ConfirmTeamInvitationService,)
+
+	achievement.Post("/team/invitations/:inviteId/decline", middleware.RequirePermission("achievement:create"), svc.DeclineTeamInvitationService,)
+
 	achievement.Get("/:id", middleware.RequirePermission("achievement:read"), svc.GetAchievementDetailService,)
 
-	achievement.Post("/", middleware.RequirePermission("achievement:create"), svc.CreateAchievementService,)
+	achievement.Post("/", middleware.RequirePermission("achievement:create"), idempotent, svc.CreateAchievementService,)
 
 	achievement.Put("/:id", middleware.RequirePermission("achievement:update"), svc.UpdateAchievementService,)
 
 	achievement.Delete("/:id", middleware.RequirePermission("achievement:delete"), svc.DeleteAchievementService,)
 
-	achievement.Post("/:id/submit", middleware.RequirePermission("achievement:update"), svc.SubmitAchievementService,)
+	achievement.Post("/:id/reopen", middleware.RequirePermission("user:manage"), svc.ReopenAchievementService,)
 
-	achievement.Post("/:id/verify", middleware.RequirePermission("achievement:verify"), svc.VerifyAchievementService,)
+	achievement.Post("/:id/restore", middleware.RequirePermission("achievement:delete"), svc.RestoreAchievementService,)
 
-	achievement.Post("/:id/reject", middleware.RequirePermission("achievement:verify"), svc.RejectAchievementService,)
+	achievement.Delete("/:id/purge", middleware.RequirePermission("user:manage"), svc.PurgeAchievementService,)
+
+	achievement.Post("/:id/submit", middleware.RequirePermission("achievement:update"), idempotent, svc.SubmitAchievementService,)
+
+	achievement.Post("/:id/withdraw", middleware.RequirePermission("achievement:update"), svc.WithdrawAchievementService,)
+
+	achievement.Post("/:id/review/start", middleware.RequirePermission("achievement:verify"), svc.StartReviewService,)
+
+	achievement.Post("/:id/verify", middleware.RequirePermission("achievement:verify"), idempotent, svc.VerifyAchievementService,)
+
+	achievement.Post("/:id/reject", middleware.RequirePermission("achievement:verify"), idempotent, svc.RejectAchievementService,)
 
 	achievement.Get("/:id/history", middleware.RequirePermission("achievement:read"), svc.GetHistoryService,)
 
-	achievement.Post("/:id/attachments", middleware.RequirePermission("achievement:update"), svc.UploadAttachmentsService,)
+	achievement.Get("/:id/revisions", middleware.RequirePermission("achievement:read"), svc.ListRevisionsService,)
+
+	achievement.Get("/:id/revisions/diff", middleware.RequirePermission("achievement:read"), svc.DiffRevisionsService,)
+
+	achievement.Get("/:id/revisions/:rev", middleware.RequirePermission("achievement:read"), svc.GetRevisionService,)
+
+	achievement.Post("/:id/attachments", middleware.RequirePermission("achievement:update"), idempotent, svc.UploadAttachmentsService,)
+
+	achievement.Get("/:id/team", middleware.RequirePermission("achievement:read"), svc.GetTeamService,)
+
+	achievement.Post("/:id/team/invite", middleware.RequirePermission("achievement:update"), svc.InviteTeamMembersService,)
 }
\ No newline at end of file
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go-fiber/app/service"
+	"go-fiber/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func AchievementTypeRoutes(app *fiber.App, mongoDB *mongo.Database) {
+	svc := service.NewAchievementTypeService(mongoDB)
+	types := app.Group("/api/v1/achievement-types", middleware.AuthRequired())
+
+	types.Get("/", middleware.RequirePermission("achievement:read"), svc.ListTypesService)
+
+	types.Get("/:code", middleware.RequirePermission("achievement:read"), svc.GetTypeService)
+
+	types.Get("/:code/versions", middleware.RequirePermission("user:manage"), svc.ListVersionsService)
+
+	types.Post("/", middleware.RequirePermission("user:manage"), svc.CreateTypeService)
+
+	types.Put("/:code", middleware.RequirePermission("user:manage"), svc.UpdateTypeService)
+
+	types.Delete("/:code", middleware.RequirePermission("user:manage"), svc.DeleteTypeService)
+}
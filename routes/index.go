@@ -13,4 +13,7 @@ func RegisterRoutes(app *fiber.App, db *sql.DB, mongoDB *mongo.Database) {
     StudentRoutes(app, db, mongoDB)
     LecturerRoutes(app, db)
 	AchievementRoutes(app, db, mongoDB)
+	AchievementTypeRoutes(app, mongoDB)
+	NotificationRoutes(app, db)
+	ReportRoutes(app, db, mongoDB)
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/gofiber/fiber/v2"
+	"go-fiber/app/service"
+	"go-fiber/middleware"
+)
+
+func NotificationRoutes(app *fiber.App, db *sql.DB) {
+	svc := service.NewNotificationService(db)
+	notification := app.Group("/api/v1/notifications", middleware.AuthRequired())
+
+	notification.Get("/", svc.ListNotificationsService)
+
+	notification.Put("/read-all", svc.MarkAllReadService)
+
+	notification.Put("/:id/read", svc.MarkReadService)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"database/sql"
+
+	"github.com/gofiber/fiber/v2"
+	"go-fiber/app/service"
+	"go-fiber/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func ReportRoutes(app *fiber.App, db *sql.DB, mongoDB *mongo.Database) {
+	svc := service.NewReportService(db, mongoDB)
+	report := app.Group("/api/v1/reports", middleware.AuthRequired())
+	idempotent := middleware.Idempotency(db)
+
+	report.Get("/statistics", middleware.RequirePermission("report:read"), svc.StatisticsService)
+
+	report.Get("/leaderboards", middleware.RequirePermission("report:read"), svc.LeaderboardService)
+
+	report.Put("/leaderboards/opt-out", middleware.RequirePermission("achievement:create"), svc.LeaderboardOptOutService)
+
+	report.Get("/advisor-workload", middleware.RequirePermission("report:read"), svc.AdvisorWorkloadService)
+
+	report.Get("/advisor-workload/:id", middleware.RequirePermission("report:read"), svc.AdvisorWorkloadDetailService)
+
+	report.Get("/students/:id/summary", middleware.RequirePermission("achievement:read"), svc.StudentSummaryService)
+
+	report.Post("/students/:id/skpi", middleware.RequirePermission("achievement:read"), idempotent, svc.GenerateSKPIService)
+}
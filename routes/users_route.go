@@ -34,4 +34,12 @@ func UserRoutes(app *fiber.App, db *sql.DB) {
     user.Put("/:id/role", func(c *fiber.Ctx) error {
         return service.AssignRoleService(c, db)
     })
+
+    user.Get("/:id/study-programs", func(c *fiber.Ctx) error {
+        return service.GetStudyProgramsService(c, db)
+    })
+
+    user.Put("/:id/study-programs", func(c *fiber.Ctx) error {
+        return service.AssignStudyProgramsService(c, db)
+    })
 }
\ No newline at end of file
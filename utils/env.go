@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetEnvInt membaca variabel environment bertipe integer, atau def jika kosong/tidak valid.
+func GetEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// GetEnvBool membaca variabel environment bertipe boolean, atau def jika kosong/tidak valid.
+func GetEnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
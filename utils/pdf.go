@@ -0,0 +1,366 @@
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PDF adalah penulis dokumen PDF sederhana tanpa dependensi eksternal. Hanya
+// mendukung font standar Helvetica dan Helvetica-Bold (WinAnsiEncoding), teks,
+// garis, paragraf dengan word-wrap, dan tabel sederhana pada kertas A4.
+type PDF struct {
+	Title   string
+	Author  string
+	Subject string
+
+	pages  []*bytes.Buffer
+	cur    *bytes.Buffer
+	bold   bool
+	size   float64
+	y      float64
+	header []pdfColumn
+	footer string
+}
+
+type pdfColumn struct {
+	width float64
+	text  string
+}
+
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 50.0
+	pdfLineFactor = 1.35
+)
+
+// Lebar glyph per 1000 unit untuk karakter ASCII 32..126.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// Karakter Unicode yang punya posisi khusus di WinAnsiEncoding.
+var winAnsiSpecial = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+func NewPDF() *PDF {
+	p := &PDF{size: 11}
+	p.AddPage()
+	return p
+}
+
+// ContentWidth adalah lebar area tulis di antara margin kiri dan kanan.
+func (p *PDF) ContentWidth() float64 {
+	return pdfPageWidth - 2*pdfMargin
+}
+
+func (p *PDF) AddPage() {
+	p.cur = &bytes.Buffer{}
+	p.pages = append(p.pages, p.cur)
+	p.y = pdfPageHeight - pdfMargin
+	if len(p.header) > 0 {
+		p.drawRow(p.header, true)
+	}
+}
+
+func (p *PDF) SetFont(bold bool, size float64) {
+	p.bold = bold
+	p.size = size
+}
+
+// SetFooter mengatur teks kiri footer; nomor halaman ditambahkan di kanan.
+func (p *PDF) SetFooter(text string) {
+	p.footer = text
+}
+
+func (p *PDF) lineHeight() float64 {
+	return p.size * pdfLineFactor
+}
+
+func (p *PDF) ensureSpace(h float64) {
+	if p.y-h < pdfMargin+20 {
+		p.AddPage()
+	}
+}
+
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 32 && r < 127:
+			out = append(out, byte(r))
+		case r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiSpecial[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func (p *PDF) stringWidth(s string, bold bool, size float64) float64 {
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, b := range encodeWinAnsi(s) {
+		if b >= 32 && b < 127 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+func escapePDFString(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '\\', '(', ')':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func (p *PDF) textAt(x, y float64, s string, bold bool, size float64) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.cur, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font, size, x, y, escapePDFString(encodeWinAnsi(s)))
+}
+
+// wrap memecah teks menjadi baris-baris yang muat dalam lebar tertentu.
+func (p *PDF) wrap(text string, width float64, bold bool, size float64) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := ""
+		for _, w := range words {
+			// Kata yang lebih panjang dari satu baris dipotong paksa.
+			for p.stringWidth(w, bold, size) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				cut := len([]rune(w))
+				for cut > 1 && p.stringWidth(string([]rune(w)[:cut]), bold, size) > width {
+					cut--
+				}
+				lines = append(lines, string([]rune(w)[:cut]))
+				w = string([]rune(w)[cut:])
+			}
+			candidate := w
+			if line != "" {
+				candidate = line + " " + w
+			}
+			if p.stringWidth(candidate, bold, size) > width && line != "" {
+				lines = append(lines, line)
+				line = w
+			} else {
+				line = candidate
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Paragraph menulis teks dengan word-wrap mulai dari posisi kursor.
+func (p *PDF) Paragraph(text string) {
+	p.ParagraphAligned(text, "left")
+}
+
+// ParagraphAligned menulis teks rata kiri, tengah ("center"), atau kanan ("right").
+func (p *PDF) ParagraphAligned(text, align string) {
+	for _, line := range p.wrap(text, p.ContentWidth(), p.bold, p.size) {
+		p.ensureSpace(p.lineHeight())
+		p.y -= p.lineHeight()
+		x := pdfMargin
+		switch align {
+		case "center":
+			x += (p.ContentWidth() - p.stringWidth(line, p.bold, p.size)) / 2
+		case "right":
+			x += p.ContentWidth() - p.stringWidth(line, p.bold, p.size)
+		}
+		p.textAt(x, p.y, line, p.bold, p.size)
+	}
+}
+
+// KeyValue menulis pasangan label dan nilai dalam dua kolom.
+func (p *PDF) KeyValue(label, value string, labelWidth float64) {
+	lines := p.wrap(value, p.ContentWidth()-labelWidth, false, p.size)
+	p.ensureSpace(p.lineHeight() * float64(len(lines)))
+	for i, line := range lines {
+		p.y -= p.lineHeight()
+		if i == 0 {
+			p.textAt(pdfMargin, p.y, label, true, p.size)
+		}
+		p.textAt(pdfMargin+labelWidth, p.y, line, false, p.size)
+	}
+}
+
+func (p *PDF) Space(h float64) {
+	p.y -= h
+}
+
+// Rule menggambar garis horizontal selebar area tulis.
+func (p *PDF) Rule() {
+	p.ensureSpace(6)
+	p.y -= 4
+	fmt.Fprintf(p.cur, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, p.y, pdfPageWidth-pdfMargin, p.y)
+	p.y -= 4
+}
+
+// TableHeader menulis baris judul tabel dan mengulanginya di setiap halaman
+// baru sampai EndTable dipanggil.
+func (p *PDF) TableHeader(widths []float64, titles []string) {
+	cols := make([]pdfColumn, len(widths))
+	for i := range widths {
+		cols[i] = pdfColumn{width: widths[i], text: titles[i]}
+	}
+	p.ensureSpace(p.lineHeight() * 3)
+	p.header = cols
+	p.drawRow(cols, true)
+}
+
+func (p *PDF) TableRow(widths []float64, cells []string) {
+	cols := make([]pdfColumn, len(widths))
+	for i := range widths {
+		cols[i] = pdfColumn{width: widths[i], text: cells[i]}
+	}
+	p.drawRow(cols, false)
+}
+
+func (p *PDF) EndTable() {
+	p.header = nil
+}
+
+func (p *PDF) drawRow(cols []pdfColumn, bold bool) {
+	const pad = 3.0
+	wrapped := make([][]string, len(cols))
+	rows := 1
+	for i, col := range cols {
+		wrapped[i] = p.wrap(col.text, col.width-2*pad, bold, p.size)
+		if len(wrapped[i]) > rows {
+			rows = len(wrapped[i])
+		}
+	}
+	height := float64(rows)*p.lineHeight() + 2*pad
+	if p.y-height < pdfMargin+20 {
+		header := p.header
+		p.AddPage()
+		if bold && header != nil {
+			// Header sudah digambar oleh AddPage.
+			return
+		}
+	}
+
+	top := p.y
+	x := pdfMargin
+	for i, col := range cols {
+		fmt.Fprintf(p.cur, "0.5 w %.2f %.2f %.2f %.2f re S\n", x, top-height, col.width, height)
+		for j, line := range wrapped[i] {
+			p.textAt(x+pad, top-pad-float64(j+1)*p.lineHeight()+p.size*0.3, line, bold, p.size)
+		}
+		x += col.width
+	}
+	p.y = top - height
+}
+
+// Output menyusun seluruh halaman menjadi file PDF.
+func (p *PDF) Output() ([]byte, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	n := len(p.pages)
+	// Objek 1: catalog, 2: pages, 3-4: font, 5: info, lalu pasangan page+content.
+	kids := make([]string, n)
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), n))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Title (%s) /Author (%s) /Subject (%s) /Producer (go-fiber) /CreationDate (D:%s) >>",
+		escapePDFString(encodeWinAnsi(p.Title)),
+		escapePDFString(encodeWinAnsi(p.Author)),
+		escapePDFString(encodeWinAnsi(p.Subject)),
+		time.Now().Format("20060102150405")))
+
+	for i, page := range p.pages {
+		content := bytes.NewBuffer(append([]byte(nil), page.Bytes()...))
+		footerY := pdfMargin / 2
+		if p.footer != "" {
+			fmt.Fprintf(content, "BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n",
+				pdfMargin, footerY, escapePDFString(encodeWinAnsi(p.footer)))
+		}
+		pageLabel := fmt.Sprintf("Halaman %d dari %d", i+1, n)
+		fmt.Fprintf(content, "BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n",
+			pdfPageWidth-pdfMargin-p.stringWidth(pageLabel, false, 8), footerY, pageLabel)
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(content.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 7+2*i))
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes(), nil
+}